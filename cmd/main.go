@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"time"
 
+	"github.com/lotekdan/go-browser-history/internal/browser"
 	"github.com/lotekdan/go-browser-history/internal/config"
 	"github.com/lotekdan/go-browser-history/internal/server"
 	"github.com/lotekdan/go-browser-history/internal/service"
@@ -16,13 +20,55 @@ var Version string = "dev"
 func main() {
 	cfg := config.NewDefaultConfig()
 	var browsers []string
+	var profileDirs []string
 	var mode string
+	var since, until string
+
+	// applySelection copies the browser and profile directory flags shared by every command into cfg.
+	applySelection := func() {
+		cfg.Browser = strings.Join(browsers, ",")
+		dirs, err := config.ParseProfileDirs(profileDirs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Critical error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.ProfileDirs = dirs
+	}
+
+	// applyTimeRange resolves the --since and --until flags of the time-bound commands into cfg.
+	// Without --since the range starts --days before its end.
+	applyTimeRange := func() {
+		now := time.Now()
+		cfg.EndTime = now
+		if until != "" {
+			endTime, err := config.ParseTime(until, now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Critical error: invalid --until: %v\n", err)
+				os.Exit(1)
+			}
+			cfg.EndTime = endTime
+		}
+		if since != "" {
+			startTime, err := config.ParseTime(since, now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Critical error: invalid --since: %v\n", err)
+				os.Exit(1)
+			}
+			if startTime.After(cfg.EndTime) {
+				fmt.Fprintf(os.Stderr, "Critical error: --since %s is after --until %s\n", startTime.Format(time.RFC3339), cfg.EndTime.Format(time.RFC3339))
+				os.Exit(1)
+			}
+			cfg.StartTime = startTime
+			cfg.CustomRange = true
+		}
+	}
 
 	rootCmd := &cobra.Command{
 		Use:   "go-browser-history",
-		Short: "Retrieve browser history from Chrome, Edge, Brave, or Firefox",
+		Short: "Retrieve browser history from Chromium- and Firefox-based browsers",
 		Run: func(cmd *cobra.Command, args []string) {
-			cfg.Browser = strings.Join(browsers, ",")
+			applySelection()
+			applyTimeRange()
 			switch mode {
 			case "api":
 				cfg.Mode = "api"
@@ -34,7 +80,20 @@ func main() {
 				cfg.Mode = "cli"
 				historyService := service.NewHistoryService(nil)
 				browserList := parseBrowsers(cfg.Browser)
-				entries, err := historyService.GetHistory(cfg, browserList)
+				// Write entries as they are read rather than collecting a potentially large history first
+				var report service.SourceReport
+				entries := historyService.StreamHistory(cmd.Context(), cfg, browserList, &report)
+				var err error
+				if cfg.JSONOutput && cfg.Sources {
+					err = historyService.WriteEnvelope(entries, &report, cfg, os.Stdout)
+				} else {
+					err = historyService.WriteResults(entries, cfg, os.Stdout)
+				}
+				if !cfg.JSONOutput && len(report.Sources) > 0 {
+					// Summarize how each profile was read, including those skipped for errors
+					fmt.Fprintln(os.Stderr, "Sources:")
+					historyService.OutputSources(&report, os.Stderr)
+				}
 				if err != nil {
 					if cfg.JSONOutput {
 						fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve history: %v"}`, err)
@@ -43,20 +102,154 @@ func main() {
 					}
 					os.Exit(1)
 				}
-				historyService.OutputResults(entries, cfg, os.Stdout)
 			}
 		},
 	}
+
+	bookmarksCmd := &cobra.Command{
+		Use:   "bookmarks",
+		Short: "List bookmarks from Chromium- and Firefox-based browsers",
+		Run: func(cmd *cobra.Command, args []string) {
+			applySelection()
+			cfg.Mode = "cli"
+			bookmarkService := service.NewBookmarkService(nil)
+			entries, err := bookmarkService.GetBookmarks(cmd.Context(), cfg, parseBrowsers(cfg.Browser))
+			if err != nil {
+				if cfg.JSONOutput {
+					fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve bookmarks: %v"}`, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to retrieve bookmarks: %v\n", err)
+				}
+				os.Exit(1)
+			}
+			bookmarkService.OutputBookmarks(entries, cfg, os.Stdout)
+		},
+	}
+
+	downloadsCmd := &cobra.Command{
+		Use:   "downloads",
+		Short: "List downloads from Chromium- and Firefox-based browsers",
+		Run: func(cmd *cobra.Command, args []string) {
+			applySelection()
+			applyTimeRange()
+			cfg.Mode = "cli"
+			downloadService := service.NewDownloadService(nil)
+			entries, err := downloadService.GetDownloads(cmd.Context(), cfg, parseBrowsers(cfg.Browser))
+			if err != nil {
+				if cfg.JSONOutput {
+					fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve downloads: %v"}`, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to retrieve downloads: %v\n", err)
+				}
+				os.Exit(1)
+			}
+			downloadService.OutputDownloads(entries, cfg, os.Stdout)
+		},
+	}
+	downloadsCmd.Flags().IntVarP(&cfg.HistoryDays, "days", "d", cfg.HistoryDays, "Number of days of downloads to retrieve")
+
+	searchesCmd := &cobra.Command{
+		Use:   "searches",
+		Short: "List search terms from address bar keyword searches and search engine result URLs",
+		Run: func(cmd *cobra.Command, args []string) {
+			applySelection()
+			applyTimeRange()
+			cfg.Mode = "cli"
+			searchTermService := service.NewSearchTermService(nil)
+			entries, err := searchTermService.GetSearchTerms(cmd.Context(), cfg, parseBrowsers(cfg.Browser))
+			if err != nil {
+				if cfg.JSONOutput {
+					fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve search terms: %v"}`, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to retrieve search terms: %v\n", err)
+				}
+				os.Exit(1)
+			}
+			searchTermService.OutputSearchTerms(entries, cfg, os.Stdout)
+		},
+	}
+	searchesCmd.Flags().IntVarP(&cfg.HistoryDays, "days", "d", cfg.HistoryDays, "Number of days of searches to retrieve")
+
+	var chainTarget service.ChainTarget
+	chainCmd := &cobra.Command{
+		Use:   "chain",
+		Short: "Show the navigation path of links and redirects that led to a URL or visit",
+		Run: func(cmd *cobra.Command, args []string) {
+			applySelection()
+			applyTimeRange()
+			cfg.Mode = "cli"
+			chainService := service.NewChainService(nil)
+			entries, err := chainService.GetChains(cmd.Context(), cfg, parseBrowsers(cfg.Browser), chainTarget)
+			if err != nil {
+				if cfg.JSONOutput {
+					fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve navigation chains: %v"}`, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to retrieve navigation chains: %v\n", err)
+				}
+				os.Exit(1)
+			}
+			chainService.OutputChains(entries, cfg, os.Stdout)
+		},
+	}
+	chainCmd.Flags().StringVar(&chainTarget.URL, "url", "", "URL whose visits' navigation chains to show")
+	chainCmd.Flags().Int64Var(&chainTarget.VisitID, "visit-id", 0, "Visit id (from JSON history output) whose navigation chain to show")
+	chainCmd.MarkFlagsOneRequired("url", "visit-id")
+	chainCmd.Flags().StringVar(&chainTarget.Profile, "profile", "", "Profile, by directory or display name, that --visit-id belongs to; needs a single --browser")
+	chainCmd.MarkFlagsMutuallyExclusive("url", "visit-id")
+	chainCmd.MarkFlagsRequiredTogether("visit-id", "profile")
+	chainCmd.Flags().IntVarP(&cfg.HistoryDays, "days", "d", cfg.HistoryDays, "Number of days of history to search for the chain")
+
+	tabsCmd := &cobra.Command{
+		Use:   "tabs",
+		Short: "List open and recently closed windows and tabs from saved browser sessions",
+		Run: func(cmd *cobra.Command, args []string) {
+			applySelection()
+			cfg.Mode = "cli"
+			tabService := service.NewTabService(nil)
+			entries, err := tabService.GetTabs(cmd.Context(), cfg, parseBrowsers(cfg.Browser))
+			if err != nil {
+				if cfg.JSONOutput {
+					fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve tabs: %v"}`, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Failed to retrieve tabs: %v\n", err)
+				}
+				os.Exit(1)
+			}
+			tabService.OutputTabs(entries, cfg, os.Stdout)
+		},
+	}
+	rootCmd.AddCommand(bookmarksCmd, downloadsCmd, searchesCmd, chainCmd, tabsCmd)
+
 	rootCmd.Flags().IntVarP(&cfg.HistoryDays, "days", "d", cfg.HistoryDays, "Number of days of history to retrieve")
-	rootCmd.Flags().StringSliceVarP(&browsers, "browser", "b", nil, "Browser types (chrome, edge, brave, firefox)")
-	rootCmd.Flags().BoolVarP(&cfg.JSONOutput, "json", "j", false, "Output results in JSON format (CLI only)")
-	rootCmd.Flags().BoolVar(&cfg.PrettyPrint, "pretty", false, "For JSON output providing a pretty print format for reading")
+	for _, timedCmd := range []*cobra.Command{rootCmd, downloadsCmd, searchesCmd, chainCmd} {
+		timedCmd.Flags().StringVar(&since, "since", "", "Start of the time range: RFC3339, a date (2006-01-02), a duration ago (2h, 3d), today, yesterday or last monday; overrides --days")
+		timedCmd.Flags().StringVar(&until, "until", "", "End of the time range, in the same forms as --since (default now)")
+		timedCmd.MarkFlagsMutuallyExclusive("days", "since")
+	}
+
+	rootCmd.PersistentFlags().StringSliceVarP(&browsers, "browser", "b", nil, "Browser types ("+strings.Join(browser.Names(), ", ")+")")
+	rootCmd.PersistentFlags().StringArrayVar(&profileDirs, "profile-dir", nil, "Read an explicit user data or profile directory instead of the default locations, as browser=path (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&cfg.Archives, "archive", nil, "Read browser profile folders bundled in a .zip, .tar or .tar.gz archive instead of the default locations (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Root, "root", "", "Read profiles from a disk image mounted at this directory instead of the running system")
+	rootCmd.PersistentFlags().StringVar(&cfg.TargetOS, "target-os", "", "Operating system of the image under --root (windows, darwin, linux); defaults to the running OS")
+	rootCmd.PersistentFlags().StringVar(&cfg.Home, "home", "", "Home directory inside --root to read, e.g. /home/alice or C:\\Users\\alice")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AllUsers, "all-users", false, "Read every local user's profiles (/home/*, /Users/* or C:\\Users\\*), also under --root")
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.Users, "user", nil, "Only read these usernames' profiles; implies --all-users")
+	rootCmd.PersistentFlags().BoolVarP(&cfg.JSONOutput, "json", "j", false, "Output results in JSON format (CLI only)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.PrettyPrint, "pretty", false, "For JSON output providing a pretty print format for reading")
 	rootCmd.Flags().StringVarP(&mode, "mode", "m", "cli", "Run mode: 'cli' (default) or 'api'")
 	rootCmd.Flags().StringVarP(&cfg.Port, "port", "p", cfg.Port, "Port for API mode")
-	rootCmd.Flags().BoolVarP(&cfg.Debug, "debug", "", false, "Enable debug logging")
+	rootCmd.Flags().BoolVar(&cfg.Sources, "sources", false, "Wrap JSON output in an object whose \"sources\" field reports how each profile was read")
+	rootCmd.Flags().DurationVar(&cfg.RequestTimeout, "request-timeout", 0, "Maximum time an API request may spend reading profiles, e.g. 30s (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&cfg.Concurrency, "concurrency", cfg.Concurrency, "Number of profile databases to copy and start querying at once; every profile stays open until its history is written")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Strict, "strict", false, "Fail if any located profile cannot be read instead of skipping it")
+	rootCmd.PersistentFlags().BoolVarP(&cfg.Debug, "debug", "", false, "Enable debug logging")
 	rootCmd.Version = Version
 
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel any copy or query in progress on Ctrl-C so temporary database copies are removed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Critical error: %v\n", err)
 		os.Exit(1)
 	}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"strings"
 	"sync"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/lotekdan/go-browser-history/internal/config"
 	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/service"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,15 +25,34 @@ type MockHistoryService struct {
 	mock.Mock
 }
 
-func (m *MockHistoryService) GetHistory(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+func (m *MockHistoryService) GetHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
 	args := m.Called(cfg, selectedBrowsers)
 	return args.Get(0).([]history.OutputEntry), args.Error(1)
 }
 
+func (m *MockHistoryService) StreamHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string, report *service.SourceReport) iter.Seq2[history.OutputEntry, error] {
+	args := m.Called(cfg, selectedBrowsers, report)
+	return args.Get(0).(iter.Seq2[history.OutputEntry, error])
+}
+
+func (m *MockHistoryService) WriteResults(entries iter.Seq2[history.OutputEntry, error], cfg *config.Config, writer io.Writer) error {
+	args := m.Called(entries, cfg, writer)
+	return args.Error(0)
+}
+
+func (m *MockHistoryService) WriteEnvelope(entries iter.Seq2[history.OutputEntry, error], report *service.SourceReport, cfg *config.Config, writer io.Writer) error {
+	args := m.Called(entries, report, cfg, writer)
+	return args.Error(0)
+}
+
 func (m *MockHistoryService) OutputResults(entries []history.OutputEntry, cfg *config.Config, writer io.Writer) {
 	m.Called(entries, cfg, writer)
 }
 
+func (m *MockHistoryService) OutputSources(report *service.SourceReport, writer io.Writer) {
+	m.Called(report, writer)
+}
+
 // SetupRootCmd creates a testable rootCmd with mocked dependencies
 func setupRootCmd(t *testing.T, mockService *MockHistoryService) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
 	cfg := config.NewDefaultConfig()
@@ -49,7 +71,18 @@ func setupRootCmd(t *testing.T, mockService *MockHistoryService) (*cobra.Command
 			default: // CLI mode
 				historyService := mockService
 				browserList := parseBrowsers(cfg.Browser)
-				entries, err := historyService.GetHistory(cfg, browserList)
+				var report service.SourceReport
+				entries := historyService.StreamHistory(cmd.Context(), cfg, browserList, &report)
+				var err error
+				if cfg.JSONOutput && cfg.Sources {
+					err = historyService.WriteEnvelope(entries, &report, cfg, os.Stdout)
+				} else {
+					err = historyService.WriteResults(entries, cfg, os.Stdout)
+				}
+				if !cfg.JSONOutput && len(report.Sources) > 0 {
+					fmt.Fprintln(os.Stderr, "Sources:")
+					historyService.OutputSources(&report, os.Stderr)
+				}
 				if err != nil {
 					if cfg.JSONOutput {
 						fmt.Fprintf(os.Stderr, `{"error": "Failed to retrieve history: %v"}`, err)
@@ -58,7 +91,6 @@ func setupRootCmd(t *testing.T, mockService *MockHistoryService) (*cobra.Command
 					}
 					return // Avoid os.Exit in test
 				}
-				historyService.OutputResults(entries, cfg, os.Stdout)
 			}
 		},
 	}
@@ -68,6 +100,7 @@ func setupRootCmd(t *testing.T, mockService *MockHistoryService) (*cobra.Command
 	rootCmd.Flags().StringSliceVarP(&browsers, "browser", "b", nil, "Browser types (chrome, edge, brave, firefox)")
 	rootCmd.Flags().BoolVarP(&cfg.JSONOutput, "json", "j", false, "Output results in JSON format (CLI only)")
 	rootCmd.Flags().BoolVar(&cfg.PrettyPrint, "pretty", false, "For JSON output providing a pretty print format")
+	rootCmd.Flags().BoolVar(&cfg.Sources, "sources", false, "Wrap JSON output in an object reporting how each profile was read")
 	rootCmd.Flags().StringVarP(&mode, "mode", "m", "cli", "Run mode: 'cli' (default) or 'api'")
 	rootCmd.Flags().StringVarP(&cfg.Port, "port", "p", cfg.Port, "Port for API mode")
 	rootCmd.Flags().BoolVarP(&cfg.Debug, "debug", "", false, "Enable debug logging")
@@ -124,7 +157,7 @@ func TestMainCmd(t *testing.T) {
 		mockService := new(MockHistoryService)
 		rootCmd, stdout, stderr := setupRootCmd(t, mockService)
 
-		// Mock GetHistory
+		// Mock StreamHistory
 		entries := []history.OutputEntry{
 			{
 				Timestamp: "2025-04-06T12:00:00Z",
@@ -133,11 +166,11 @@ func TestMainCmd(t *testing.T) {
 				Browser:   "chrome",
 			},
 		}
-		mockService.On("GetHistory", mock.Anything, []string{"chrome"}).Return(entries, nil)
-		mockService.On("OutputResults", entries, mock.Anything, os.Stdout).Run(func(args mock.Arguments) {
+		mockService.On("StreamHistory", mock.Anything, []string{"chrome"}, mock.Anything).Return(history.Values(entries, nil))
+		mockService.On("WriteResults", mock.Anything, mock.Anything, os.Stdout).Run(func(args mock.Arguments) {
 			writer := args.Get(2).(*os.File)
 			_, _ = writer.WriteString("2025-04-06T12:00:00Z           Example                                            (https://example.com) [0] [0] [] [chrome] []\n")
-		})
+		}).Return(nil)
 
 		// Set flags and execute
 		rootCmd.SetArgs([]string{"--browser", "chrome", "--mode", "cli"})
@@ -162,8 +195,9 @@ func TestMainCmd(t *testing.T) {
 		mockService := new(MockHistoryService)
 		rootCmd, stdout, stderr := setupRootCmd(t, mockService)
 
-		// Mock GetHistory to return an error
-		mockService.On("GetHistory", mock.Anything, []string{"firefox"}).Return(([]history.OutputEntry)(nil), errors.New("history error"))
+		// Mock StreamHistory to return an error
+		mockService.On("StreamHistory", mock.Anything, []string{"firefox"}, mock.Anything).Return(history.Values[history.OutputEntry](nil, errors.New("history error")))
+		mockService.On("WriteResults", mock.Anything, mock.Anything, os.Stdout).Return(errors.New("history error"))
 
 		// Set flags and execute
 		rootCmd.SetArgs([]string{"--browser", "firefox", "--mode", "cli"})
@@ -188,8 +222,9 @@ func TestMainCmd(t *testing.T) {
 		mockService := new(MockHistoryService)
 		rootCmd, stdout, stderr := setupRootCmd(t, mockService)
 
-		// Mock GetHistory to return an error with JSON output
-		mockService.On("GetHistory", mock.Anything, []string{"firefox"}).Return(([]history.OutputEntry)(nil), errors.New("history error"))
+		// Mock StreamHistory to return an error with JSON output
+		mockService.On("StreamHistory", mock.Anything, []string{"firefox"}, mock.Anything).Return(history.Values[history.OutputEntry](nil, errors.New("history error")))
+		mockService.On("WriteResults", mock.Anything, mock.Anything, os.Stdout).Return(errors.New("history error"))
 
 		// Set flags and execute
 		rootCmd.SetArgs([]string{"--browser", "firefox", "--mode", "cli", "--json"})
@@ -209,4 +244,60 @@ func TestMainCmd(t *testing.T) {
 		assert.Equal(t, `{"error": "Failed to retrieve history: history error"}`, stderr.String())
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("CLI_SourcesSummary", func(t *testing.T) {
+		mockService := new(MockHistoryService)
+		rootCmd, stdout, stderr := setupRootCmd(t, mockService)
+
+		// Report one profile that could not be read
+		mockService.On("StreamHistory", mock.Anything, []string{"chrome"}, mock.Anything).Run(func(args mock.Arguments) {
+			report := args.Get(2).(*service.SourceReport)
+			report.Sources = []history.SourceResult{{Browser: "chrome", Profile: "Default", Found: true, Error: "database is locked"}}
+		}).Return(history.Values[history.OutputEntry](nil, nil))
+		mockService.On("WriteResults", mock.Anything, mock.Anything, os.Stdout).Return(nil)
+		mockService.On("OutputSources", mock.Anything, os.Stderr).Run(func(args mock.Arguments) {
+			report := args.Get(0).(*service.SourceReport)
+			_, _ = args.Get(1).(*os.File).WriteString(report.Sources[0].Profile + " " + report.Sources[0].Error + "\n")
+		})
+
+		rootCmd.SetArgs([]string{"--browser", "chrome"})
+		err := rootCmd.Execute()
+		assert.NoError(t, err)
+
+		os.Stdout.Close()
+		os.Stderr.Close()
+		wg := &sync.WaitGroup{}
+		wg.Add(2)
+		go func() { defer wg.Done(); io.Copy(stdout, os.Stdout) }()
+		go func() { defer wg.Done(); io.Copy(stderr, os.Stderr) }()
+		wg.Wait()
+
+		assert.Equal(t, "Sources:\nDefault database is locked\n", stderr.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("CLI_JSON_Sources", func(t *testing.T) {
+		mockService := new(MockHistoryService)
+		rootCmd, stdout, stderr := setupRootCmd(t, mockService)
+
+		mockService.On("StreamHistory", mock.Anything, []string{"chrome"}, mock.Anything).Return(history.Values[history.OutputEntry](nil, nil))
+		mockService.On("WriteEnvelope", mock.Anything, mock.Anything, mock.Anything, os.Stdout).Run(func(args mock.Arguments) {
+			_, _ = args.Get(3).(*os.File).WriteString(`{"entries":[],"sources":[]}` + "\n")
+		}).Return(nil)
+
+		rootCmd.SetArgs([]string{"--browser", "chrome", "--json", "--sources"})
+		err := rootCmd.Execute()
+		assert.NoError(t, err)
+
+		os.Stdout.Close()
+		os.Stderr.Close()
+		wg := &sync.WaitGroup{}
+		wg.Add(2)
+		go func() { defer wg.Done(); io.Copy(stdout, os.Stdout) }()
+		go func() { defer wg.Done(); io.Copy(stderr, os.Stderr) }()
+		wg.Wait()
+
+		assert.Empty(t, stderr.String())
+		mockService.AssertExpectations(t)
+	})
 }
@@ -1,6 +1,10 @@
 package browser
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
 	"time"
 
 	"github.com/lotekdan/go-browser-history/internal/history"
@@ -10,6 +14,79 @@ import (
 type Browser interface {
 	// GetHistoryPath retrieves the path to the browser's history database.
 	GetHistoryPaths() ([]history.HistoryPathEntry, error)
-	// ExtractHistory retrieves history entries from the database within the given time range, with optional verbose logging.
-	ExtractHistory(dbPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.HistoryEntry, error)
+	// ExtractHistory returns the history entries within the given time range, newest first, as a
+	// sequence that reads the database row by row while it is iterated, with optional verbose
+	// logging. An error, including ctx being cancelled, ends the sequence and is yielded with a
+	// zero entry.
+	ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error]
+}
+
+// DirectoryBrowser is implemented by browsers that can discover profiles in an explicit
+// user data or profile directory instead of their default OS locations.
+type DirectoryBrowser interface {
+	Browser
+	// GetHistoryPathsFrom retrieves the history database paths for the profiles found in dir.
+	GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error)
+}
+
+// EnvironmentBrowser is implemented by browsers that can resolve their default profile
+// locations against an Environment other than the running system, such as a mounted image.
+type EnvironmentBrowser interface {
+	Browser
+	// GetHistoryPathsIn retrieves the history database paths for the profiles found in env.
+	GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error)
+}
+
+// BookmarkBrowser is implemented by browsers that can read the bookmarks of the profiles
+// their history paths point to.
+type BookmarkBrowser interface {
+	Browser
+	// BookmarksFile returns the file holding the bookmarks of the profile whose history database is at historyPath.
+	BookmarksFile(historyPath string) string
+	// ExtractBookmarks retrieves the bookmarks from a copy of the profile's bookmarks file.
+	ExtractBookmarks(ctx context.Context, bookmarksPath, profile string, verbose bool) ([]history.BookmarkEntry, error)
+}
+
+// DownloadBrowser is implemented by browsers that record downloads in their history database.
+type DownloadBrowser interface {
+	Browser
+	// ExtractDownloads retrieves the downloads started within the given time range from a copy of the history database.
+	ExtractDownloads(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.DownloadEntry, error)
+}
+
+// SearchTermBrowser is implemented by browsers that record the terms of searches run from the
+// address bar in their history database.
+type SearchTermBrowser interface {
+	Browser
+	// ExtractSearchTerms retrieves the recorded searches run within the given time range from a copy of the history database, one per visit to the results page.
+	ExtractSearchTerms(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.SearchTermEntry, error)
+}
+
+// TabBrowser is implemented by browsers whose profiles save their open windows and tabs in
+// session files.
+type TabBrowser interface {
+	Browser
+	// SessionFiles returns the session files saved in the profile owning the given history database.
+	SessionFiles(historyPath string) []string
+	// ExtractSession reads the windows and tabs saved in sessionPath, a copy of the profile's
+	// sessionFile. The original file's name and modification time describe the session.
+	ExtractSession(ctx context.Context, sessionFile, sessionPath, profile string, verbose bool) (history.SessionEntry, error)
+}
+
+// ErrUnsupportedOS is returned, wrapped, when a browser has no known profile location on an
+// operating system.
+var ErrUnsupportedOS = errors.New("unsupported operating system")
+
+// unsupportedOSError reports that a browser has no known profile location on goos.
+func unsupportedOSError(goos string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedOS, goos)
+}
+
+// normalizeVisitType looks up a native visit type in a browser's mapping, reporting types the
+// mapping does not know as history.VisitUnknown.
+func normalizeVisitType(mapping map[string]history.VisitType, native string) history.VisitType {
+	if visitType, ok := mapping[native]; ok {
+		return visitType
+	}
+	return history.VisitUnknown
 }
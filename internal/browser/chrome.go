@@ -1,13 +1,14 @@
 package browser
 
 import (
+	"context"
 	"database/sql" // For SQL database interactions
 	"encoding/json"
 	"fmt" // For formatted output and error messages
 	"io/ioutil"
-	"os" // For environment variables
+	"iter"
+	"os" // For file system access
 	"path/filepath"
-	"runtime" // For OS detection
 	"strings"
 	"time" // For time conversions
 
@@ -15,42 +16,36 @@ import (
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
-// chromeHistoryQuery is the SQL query for retrieving Chrome history entries.
+// chromeHistoryQuery is the SQL query for retrieving Chrome history entries. The %[1]s verb is
+// replaced with the expression selecting a visit's referrer visit id.
 const chromeHistoryQuery = `
 	SELECT
 		url.url,
 		url.title,
 		url.visit_count,
 		url.typed_count,
-		CASE (visit.transition & 0xFF)
-			WHEN 0 THEN 'LINK'
-			WHEN 1 THEN 'TYPED'
-			WHEN 2 THEN 'AUTO_BOOKMARK'
-			WHEN 3 THEN 'AUTO_SUBFRAME'
-			WHEN 4 THEN 'MANUAL_SUBFRAME'
-			WHEN 5 THEN 'GENERATED'
-			WHEN 6 THEN 'AUTO_TOPLEVEL'
-			WHEN 7 THEN 'FORM_SUBMIT'
-			WHEN 8 THEN 'RELOAD'
-			WHEN 9 THEN 'KEYWORD'
-			WHEN 10 THEN 'KEYWORD_GENERATED'
-			ELSE 'UNKNOWN_CORE (' || (visit.transition & 0xFF) || ')'
-		END || 
-		CASE
-			WHEN (visit.transition & 0x10000000) = 0x10000000 THEN ' (REDIRECT)'
-			WHEN (visit.transition & 0x40000000) = 0x40000000 THEN ' (CLIENT_REDIRECT)'
-			WHEN (visit.transition & 0x80000000) = 0x80000000 THEN ' (SERVER_REDIRECT)'
-			WHEN (visit.transition & 0x01000000) = 0x01000000 THEN ' (FORWARD_BACK)'
-			WHEN (visit.transition & 0x02000000) = 0x02000000 THEN ' (FROM_ADDRESS_BAR)'
-			ELSE ''
-		END AS transition_desc,
-		visit.visit_time
+		visit.transition,
+		visit.visit_time,
+		visit.visit_duration,
+		visit.id,
+		%[1]s,
+		IFNULL(referrer_url.url, '')
 	FROM urls url
 	JOIN visits visit ON visit.url = url.id
+	LEFT JOIN visits referrer ON referrer.id = %[1]s
+	LEFT JOIN urls referrer_url ON referrer_url.id = referrer.url
 	WHERE visit.visit_time >= ? AND visit.visit_time <= ?
 	ORDER BY visit.visit_time DESC;`
 
-// ChromeBrowser implements the Browser interface for Google Chrome.
+// chromeFromVisit and chromeFromOrOpenVisit select the visit that led to a visit: the navigation's from_visit or,
+// for a page opened in a new tab or window, the opener_visit newer Chromium versions record.
+const (
+	chromeFromVisit       = "visit.from_visit"
+	chromeFromOrOpenVisit = "CASE WHEN visit.from_visit <> 0 THEN visit.from_visit ELSE IFNULL(visit.opener_visit, 0) END"
+)
+
+// ChromeBrowser implements the Browser interface for Google Chrome and provides the
+// profile discovery and history schema shared by every Chromium-based browser.
 type ChromeBrowser struct{}
 
 // NewChromeBrowser creates a new instance of ChromeBrowser.
@@ -60,19 +55,12 @@ func NewChromeBrowser() Browser {
 
 // GetHistoryPath retrieves collection of paths to Chrome's history database file.
 func (cb *ChromeBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
-	switch runtime.GOOS {
-	case "windows":
-		paths, _ := cb.getPaths(os.Getenv("LOCALAPPDATA") + "\\Google\\Chrome\\User Data")
-		return paths, nil
-	case "darwin":
-		paths, _ := cb.getPaths(os.Getenv("HOME") + "/Library/Application Support/Google/Chrome")
-		return paths, nil
-	case "linux":
-		paths, _ := cb.getPaths(os.Getenv("HOME") + "/.config/google-chrome")
-		return paths, nil
-	default:
-		return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-	}
+	return NewChromiumBrowser(chromeDescriptor).GetHistoryPaths()
+}
+
+// GetHistoryPathsFrom retrieves the history database paths for a user data directory or a single profile directory.
+func (cb *ChromeBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return cb.getPaths(dir)
 }
 
 // GetBrowserProfilePaths gets a collection of browser profile history paths.
@@ -97,6 +85,15 @@ func (cb *ChromeBrowser) getPaths(dir string) ([]history.HistoryPathEntry, error
 	}
 
 	var profilePaths []history.HistoryPathEntry
+	// Opera keeps its single profile directly in the user data directory, and an explicitly
+	// supplied profile directory has the same shape.
+	if info, err := os.Stat(filepath.Join(dir, "History")); err == nil && !info.IsDir() {
+		profilePaths = append(profilePaths, history.HistoryPathEntry{
+			Profile:     "Default",
+			ProfileName: cb.getProfileName(filepath.Join(dir, "Preferences")),
+			Path:        filepath.Join(dir, "History"),
+		})
+	}
 	for _, entry := range entries {
 		if entry.IsDir() {
 			if strings.HasPrefix(strings.ToLower(entry.Name()), "profile") ||
@@ -148,47 +145,76 @@ func (cb *ChromeBrowser) getProfileName(profilePath string) string {
 }
 
 // ExtractHistory extracts Chrome history entries within the given time range.
-func (cb *ChromeBrowser) ExtractHistory(historyDBPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.HistoryEntry, error) {
-	db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Chrome history database at %s: %v", historyDBPath, err)
-	}
-	defer db.Close()
+func (cb *ChromeBrowser) ExtractHistory(ctx context.Context, historyDBPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to open Chrome history database at %s: %v", historyDBPath, err))
+			return
+		}
+		defer db.Close()
 
-	chromeStartTime := TimeToChromeTime(startTime)
-	chromeEndTime := TimeToChromeTime(endTime)
+		chromeStartTime := TimeToChromeTime(startTime)
+		chromeEndTime := TimeToChromeTime(endTime)
 
-	rows, err := db.Query(chromeHistoryQuery, chromeStartTime, chromeEndTime)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query Chrome history from %s: %v", historyDBPath, err)
-	}
-	defer rows.Close()
-
-	var entries []history.HistoryEntry
-	for rows.Next() {
-		var pageURL, pageTitle, pageVisitType string
-		var pageVisitCount, pageTyped int
-		var visitTimestamp int64
-		if err := rows.Scan(&pageURL,
-			&pageTitle,
-			&pageVisitCount,
-			&pageTyped,
-			&pageVisitType,
-			&visitTimestamp); err != nil {
-			return nil, fmt.Errorf("failed to scan Chrome history row from %s: %v", historyDBPath, err)
+		referrerColumn := chromeFromVisit
+		if hasColumn(ctx, db, "visits", "opener_visit") {
+			referrerColumn = chromeFromOrOpenVisit
+		}
+		rows, err := db.QueryContext(ctx, fmt.Sprintf(chromeHistoryQuery, referrerColumn), chromeStartTime, chromeEndTime)
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to query Chrome history from %s: %v", historyDBPath, err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var pageURL, pageTitle, referrerURL string
+			var pageVisitCount, pageTyped int
+			var transition, visitTimestamp, visitDuration, visitID, referrerVisitID int64
+			if err := rows.Scan(&pageURL,
+				&pageTitle,
+				&pageVisitCount,
+				&pageTyped,
+				&transition,
+				&visitTimestamp,
+				&visitDuration,
+				&visitID,
+				&referrerVisitID,
+				&referrerURL); err != nil {
+				yield(history.HistoryEntry{}, fmt.Errorf("failed to scan Chrome history row from %s: %v", historyDBPath, err))
+				return
+			}
+			pageTransition := DecodeChromeTransition(transition)
+			if !yield(history.HistoryEntry{
+				URL:             pageURL,
+				Title:           pageTitle,
+				VisitCount:      pageVisitCount,
+				Typed:           pageTyped,
+				VisitType:       chromeVisitType(pageTransition),
+				NativeVisitType: pageTransition.String(),
+				Transition:      &pageTransition,
+				Timestamp:       ChromeTimeToTime(visitTimestamp),
+				Duration:        time.Duration(visitDuration) * time.Microsecond,
+				VisitID:         visitID,
+				ReferrerVisitID: referrerVisitID,
+				ReferrerURL:     referrerURL,
+				Profile:         profile,
+			}, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("error iterating Chrome history rows from %s: %v", historyDBPath, err))
 		}
-		entries = append(entries, history.HistoryEntry{
-			URL:        pageURL,
-			Title:      pageTitle,
-			VisitCount: pageVisitCount,
-			Typed:      pageTyped,
-			VisitType:  pageVisitType,
-			Timestamp:  ChromeTimeToTime(visitTimestamp),
-			Profile:    profile,
-		})
 	}
+}
 
-	return entries, nil
+// hasColumn reports whether table has the named column, for columns only some browser versions write.
+func hasColumn(ctx context.Context, db *sql.DB, table, column string) bool {
+	var count int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	return err == nil && count > 0
 }
 
 // TimeToChromeTime converts Go time.Time to Chrome's timestamp format (microseconds since 1601-01-01).
@@ -0,0 +1,116 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ChromiumBookmarksFile is the name of the JSON file holding a Chromium profile's bookmarks.
+const ChromiumBookmarksFile = "Bookmarks"
+
+// chromeBookmarkRoots lists the top-level folders of a Bookmarks file in the order Chrome shows them.
+var chromeBookmarkRoots = []string{"bookmark_bar", "other", "synced"}
+
+// chromeBookmarkNode is a folder or URL node of a Chromium Bookmarks file.
+type chromeBookmarkNode struct {
+	Name      string               `json:"name"`
+	Type      string               `json:"type"`
+	URL       string               `json:"url"`
+	GUID      string               `json:"guid"`
+	DateAdded string               `json:"date_added"`
+	Children  []chromeBookmarkNode `json:"children"`
+}
+
+// BookmarksFile returns the Bookmarks file stored next to the profile's History database.
+func (cb *ChromeBrowser) BookmarksFile(historyPath string) string {
+	return filepath.Join(filepath.Dir(historyPath), ChromiumBookmarksFile)
+}
+
+// ExtractBookmarks parses a Chromium Bookmarks file into bookmark entries, depth first in folder order.
+func (cb *ChromeBrowser) ExtractBookmarks(ctx context.Context, bookmarksPath, profile string, verbose bool) ([]history.BookmarkEntry, error) {
+	data, err := os.ReadFile(bookmarksPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Chrome bookmarks at %s: %v", bookmarksPath, err)
+	}
+	var file struct {
+		Roots map[string]json.RawMessage `json:"roots"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse Chrome bookmarks at %s: %v", bookmarksPath, err)
+	}
+
+	// Known roots come first; any others, such as those added by newer versions, follow by name.
+	var extraRoots []string
+	for name := range file.Roots {
+		if !slices.Contains(chromeBookmarkRoots, name) {
+			extraRoots = append(extraRoots, name)
+		}
+	}
+	sort.Strings(extraRoots)
+
+	var entries []history.BookmarkEntry
+	for _, name := range append(append([]string{}, chromeBookmarkRoots...), extraRoots...) {
+		raw, ok := file.Roots[name]
+		if !ok {
+			continue
+		}
+		// Older files keep non-folder values such as sync_transaction_version among the roots.
+		var root chromeBookmarkNode
+		if err := json.Unmarshal(raw, &root); err != nil || root.Type != "folder" {
+			continue
+		}
+		if entries, err = appendChromeBookmarks(ctx, entries, root, root.Name, profile); err != nil {
+			return nil, err
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Retrieved %d bookmarks from %s\n", len(entries), bookmarksPath)
+	}
+	return entries, nil
+}
+
+// appendChromeBookmarks appends the URL nodes below folder, whose path is folderPath, to entries.
+// It stops when ctx is cancelled.
+func appendChromeBookmarks(ctx context.Context, entries []history.BookmarkEntry, folder chromeBookmarkNode, folderPath, profile string) ([]history.BookmarkEntry, error) {
+	for _, node := range folder.Children {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch node.Type {
+		case "url":
+			entries = append(entries, history.BookmarkEntry{
+				URL:       node.URL,
+				Title:     node.Name,
+				Folder:    folderPath,
+				DateAdded: chromeBookmarkTime(node.DateAdded),
+				GUID:      node.GUID,
+				Profile:   profile,
+			})
+		case "folder":
+			var err error
+			if entries, err = appendChromeBookmarks(ctx, entries, node, folderPath+"/"+node.Name, profile); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return entries, nil
+}
+
+// chromeBookmarkTime converts a Bookmarks file timestamp, a decimal string in Chrome time, to time.Time.
+func chromeBookmarkTime(value string) time.Time {
+	chromeTimestamp, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || chromeTimestamp == 0 {
+		return time.Time{}
+	}
+	return ChromeTimeToTime(chromeTimestamp)
+}
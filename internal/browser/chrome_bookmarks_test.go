@@ -0,0 +1,125 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testChromeBookmarks = `{
+   "checksum": "0123456789abcdef",
+   "roots": {
+      "bookmark_bar": {
+         "children": [ {
+            "date_added": "13350000000000000",
+            "guid": "9b1c7e5e-0c59-4c4e-9a7f-1d2f3a4b5c6d",
+            "id": "5",
+            "name": "The Go Programming Language",
+            "type": "url",
+            "url": "https://go.dev/"
+         }, {
+            "children": [ {
+               "date_added": "0",
+               "guid": "2f0e9c1a-7d1b-4a39-8b87-5c1d9e0f1a2b",
+               "id": "7",
+               "name": "pkg.go.dev",
+               "type": "url",
+               "url": "https://pkg.go.dev/"
+            } ],
+            "date_added": "13350000000000000",
+            "guid": "c3d4e5f6-0000-4000-8000-000000000001",
+            "id": "6",
+            "name": "Docs",
+            "type": "folder"
+         } ],
+         "guid": "0bc5d13f-2cba-5d74-951f-3f233fe6c908",
+         "id": "1",
+         "name": "Bookmarks bar",
+         "type": "folder"
+      },
+      "other": {
+         "children": [ {
+            "date_added": "13350000001000000",
+            "guid": "6a7b8c9d-1111-4222-8333-944455566677",
+            "id": "8",
+            "name": "Example",
+            "type": "url",
+            "url": "https://example.com/"
+         } ],
+         "id": "2",
+         "name": "Other bookmarks",
+         "type": "folder"
+      },
+      "synced": {
+         "children": [ ],
+         "id": "3",
+         "name": "Mobile bookmarks",
+         "type": "folder"
+      }
+   },
+   "sync_transaction_version": "1",
+   "version": 1
+}`
+
+func TestChromeBrowser_BookmarksFile(t *testing.T) {
+	cb := &ChromeBrowser{}
+	historyPath := filepath.Join("profiles", "Default", "History")
+	if got, want := cb.BookmarksFile(historyPath), filepath.Join("profiles", "Default", "Bookmarks"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestChromeBrowser_ExtractBookmarks(t *testing.T) {
+	bookmarksPath := filepath.Join(t.TempDir(), "Bookmarks")
+	if err := os.WriteFile(bookmarksPath, []byte(testChromeBookmarks), 0644); err != nil {
+		t.Fatalf("Failed to write Bookmarks: %v", err)
+	}
+
+	cb := &ChromeBrowser{}
+	entries, err := cb.ExtractBookmarks(context.Background(), bookmarksPath, "Person 1", false)
+	if err != nil {
+		t.Fatalf("ExtractBookmarks failed: %v", err)
+	}
+	expected := []struct {
+		url    string
+		folder string
+		guid   string
+	}{
+		{"https://go.dev/", "Bookmarks bar", "9b1c7e5e-0c59-4c4e-9a7f-1d2f3a4b5c6d"},
+		{"https://pkg.go.dev/", "Bookmarks bar/Docs", "2f0e9c1a-7d1b-4a39-8b87-5c1d9e0f1a2b"},
+		{"https://example.com/", "Other bookmarks", "6a7b8c9d-1111-4222-8333-944455566677"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d bookmarks, got %+v", len(expected), entries)
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.URL != want.url || entry.Folder != want.folder || entry.GUID != want.guid || entry.Profile != "Person 1" {
+			t.Errorf("Bookmark %d: got %+v, want %+v", i, entry, want)
+		}
+	}
+	if want := ChromeTimeToTime(13350000000000000); !entries[0].DateAdded.Equal(want) {
+		t.Errorf("Expected date added %v, got %v", want, entries[0].DateAdded)
+	}
+	if !entries[1].DateAdded.Equal(time.Time{}) {
+		t.Errorf("Expected zero date added for a 0 timestamp, got %v", entries[1].DateAdded)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cb.ExtractBookmarks(ctx, bookmarksPath, "Person 1", false); err != context.Canceled {
+		t.Errorf("Expected a cancelled context to stop the walk, got %v", err)
+	}
+}
+
+func TestChromeBrowser_ExtractBookmarksInvalid(t *testing.T) {
+	bookmarksPath := filepath.Join(t.TempDir(), "Bookmarks")
+	if err := os.WriteFile(bookmarksPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write Bookmarks: %v", err)
+	}
+	if _, err := (&ChromeBrowser{}).ExtractBookmarks(context.Background(), bookmarksPath, "Default", false); err == nil {
+		t.Error("Expected an error for malformed Bookmarks")
+	}
+}
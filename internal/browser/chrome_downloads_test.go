@@ -0,0 +1,94 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// createChromeDownloadsDB creates a History database holding the downloads tables.
+func createChromeDownloadsDB(t *testing.T, dbPath string, started time.Time) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+        CREATE TABLE downloads (
+            id INTEGER PRIMARY KEY,
+            guid VARCHAR NOT NULL,
+            current_path LONGVARCHAR NOT NULL,
+            target_path LONGVARCHAR NOT NULL,
+            start_time INTEGER NOT NULL,
+            received_bytes INTEGER NOT NULL,
+            total_bytes INTEGER NOT NULL,
+            state INTEGER NOT NULL,
+            danger_type INTEGER NOT NULL,
+            interrupt_reason INTEGER NOT NULL,
+            end_time INTEGER NOT NULL,
+            opened INTEGER NOT NULL,
+            referrer VARCHAR NOT NULL,
+            tab_url VARCHAR NOT NULL,
+            mime_type VARCHAR(255) NOT NULL,
+            original_mime_type VARCHAR(255) NOT NULL
+        );
+        CREATE TABLE downloads_url_chains (
+            id INTEGER NOT NULL,
+            chain_index INTEGER NOT NULL,
+            url LONGVARCHAR NOT NULL,
+            PRIMARY KEY (id, chain_index)
+        );
+        INSERT INTO downloads VALUES (1, 'a', '/home/bob/Downloads/go.tar.gz', '/home/bob/Downloads/go.tar.gz', ?, 100, 100, 1, 0, 0, ?, 0,
+            'https://go.dev/dl/', 'https://go.dev/dl/', 'application/gzip', 'application/gzip');
+        INSERT INTO downloads VALUES (2, 'b', '/home/bob/Downloads/setup.crdownload', '', ?, 10, 500, 0, 1, 0, 0, 0,
+            '', '', 'application/octet-stream', 'application/octet-stream');
+        INSERT INTO downloads VALUES (3, 'c', '/home/bob/Downloads/old.zip', '/home/bob/Downloads/old.zip', ?, 1, 1, 1, 0, 0, 0, 0,
+            '', '', 'application/zip', 'application/zip');
+        INSERT INTO downloads_url_chains VALUES (1, 1, 'https://dl.google.com/go/go.tar.gz');
+        INSERT INTO downloads_url_chains VALUES (1, 0, 'https://go.dev/dl/go.tar.gz');
+        INSERT INTO downloads_url_chains VALUES (2, 0, 'https://example.com/setup.exe');
+    `, TimeToChromeTime(started), TimeToChromeTime(started.Add(time.Minute)),
+		TimeToChromeTime(started.Add(time.Hour)), TimeToChromeTime(started.Add(-72*time.Hour)))
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+}
+
+func TestChromeBrowser_ExtractDownloads(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	started := time.Now().Add(-2 * time.Hour).Truncate(time.Microsecond)
+	createChromeDownloadsDB(t, dbPath, started)
+
+	cb := &ChromeBrowser{}
+	entries, err := cb.ExtractDownloads(context.Background(), dbPath, "Person 1", time.Now().Add(-24*time.Hour), time.Now(), false)
+	if err != nil {
+		t.Fatalf("ExtractDownloads failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 downloads, got %+v", entries)
+	}
+
+	inProgress := entries[0]
+	if inProgress.TargetPath != "/home/bob/Downloads/setup.crdownload" || inProgress.State != "IN_PROGRESS" ||
+		inProgress.DangerType != "DANGEROUS_FILE" || !inProgress.EndTime.IsZero() || inProgress.TotalBytes != 500 {
+		t.Errorf("Unexpected in-progress download %+v", inProgress)
+	}
+
+	complete := entries[1]
+	if len(complete.URLChain) != 2 || complete.URLChain[0] != "https://go.dev/dl/go.tar.gz" || complete.URLChain[1] != "https://dl.google.com/go/go.tar.gz" {
+		t.Errorf("Unexpected URL chain %v", complete.URLChain)
+	}
+	if complete.State != "COMPLETE" || complete.DangerType != "NOT_DANGEROUS" || complete.Referrer != "https://go.dev/dl/" ||
+		complete.MimeType != "application/gzip" || complete.ReceivedBytes != 100 || complete.Profile != "Person 1" {
+		t.Errorf("Unexpected complete download %+v", complete)
+	}
+	if !complete.StartTime.Equal(started) || !complete.EndTime.Equal(started.Add(time.Minute)) {
+		t.Errorf("Unexpected times %v - %v", complete.StartTime, complete.EndTime)
+	}
+}
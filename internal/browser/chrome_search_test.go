@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestChromeBrowser_ExtractSearchTerms(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	visited := time.Now().Add(-2 * time.Hour).Truncate(time.Microsecond)
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	_, err = db.Exec(`
+        CREATE TABLE urls (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, typed_count INTEGER, last_visit_time INTEGER);
+        CREATE TABLE keyword_search_terms (keyword_id INTEGER NOT NULL, url_id INTEGER NOT NULL, term LONGVARCHAR NOT NULL, normalized_term LONGVARCHAR NOT NULL);
+        INSERT INTO urls VALUES (1, 'https://www.google.com/search?q=Go+Generics', 'Go Generics - Google Search', 1, 0, ?);
+        INSERT INTO urls VALUES (2, 'https://search.example.org/find?query=wiki', 'wiki', 1, 0, ?);
+        INSERT INTO urls VALUES (3, 'https://www.google.com/search?q=old', 'old', 1, 0, ?);
+        INSERT INTO keyword_search_terms VALUES (2, 1, 'Go Generics', 'go generics');
+        INSERT INTO keyword_search_terms VALUES (7, 2, 'wiki', 'wiki');
+        INSERT INTO keyword_search_terms VALUES (2, 3, 'old', 'old');
+        CREATE TABLE visits (id INTEGER PRIMARY KEY, url INTEGER NOT NULL, visit_time INTEGER NOT NULL);
+        INSERT INTO visits VALUES (1, 1, ?);
+        INSERT INTO visits VALUES (2, 2, ?);
+        INSERT INTO visits VALUES (3, 3, ?);
+        INSERT INTO visits VALUES (4, 1, ?);
+    `, TimeToChromeTime(visited), TimeToChromeTime(visited.Add(-time.Hour)), TimeToChromeTime(visited.Add(-72*time.Hour)),
+		TimeToChromeTime(visited), TimeToChromeTime(visited.Add(-time.Hour)), TimeToChromeTime(visited.Add(-72*time.Hour)), TimeToChromeTime(visited.Add(-30*time.Minute)))
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	cb := &ChromeBrowser{}
+	entries, err := cb.ExtractSearchTerms(context.Background(), dbPath, "Person 1", time.Now().Add(-24*time.Hour), time.Now(), false)
+	if err != nil {
+		t.Fatalf("ExtractSearchTerms failed: %v", err)
+	}
+	// The repeated search keeps a timestamp for each of its visits.
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 search terms, got %+v", entries)
+	}
+	want := history.SearchTermEntry{
+		Engine:    "google",
+		Term:      "Go Generics",
+		URL:       "https://www.google.com/search?q=Go+Generics",
+		Timestamp: visited,
+		Source:    history.SearchSourceKeyword,
+		Profile:   "Person 1",
+	}
+	if got := entries[0]; got.Engine != want.Engine || got.Term != want.Term || got.URL != want.URL ||
+		!got.Timestamp.Equal(want.Timestamp) || got.Source != want.Source || got.Profile != want.Profile {
+		t.Errorf("Unexpected search term %+v, want %+v", got, want)
+	}
+	if entries[1].Term != "Go Generics" || !entries[1].Timestamp.Equal(visited.Add(-30*time.Minute)) {
+		t.Errorf("Expected the earlier visit of the repeated search, got %+v", entries[1])
+	}
+	if entries[2].Engine != "search.example.org" || entries[2].Term != "wiki" {
+		t.Errorf("Expected unknown engine to fall back to its host, got %+v", entries[2])
+	}
+}
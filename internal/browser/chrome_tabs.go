@@ -0,0 +1,350 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// Session service commands written to Session_* files, from Chromium's session_service_commands.cc.
+const (
+	snssSetTabWindow                     = 0
+	snssSetTabIndexInWindow              = 2
+	snssTabNavigationPathPrunedFromBack  = 5
+	snssUpdateTabNavigation              = 6
+	snssSetSelectedNavigationIndex       = 7
+	snssTabNavigationPathPrunedFromFront = 11
+	snssSetPinnedState                   = 12
+	snssTabClosed                        = 16
+	snssWindowClosed                     = 17
+	snssLastActiveTime                   = 21
+	snssTabNavigationPathPruned          = 24
+)
+
+// Tab restore service commands written to Tabs_* files, from Chromium's
+// persistent_tab_restore_service.cc.
+const (
+	snssRestoreUpdateTabNavigation     = 1
+	snssRestoreRestoredEntry           = 2
+	snssRestoreWindow                  = 3
+	snssRestoreSelectedNavigationInTab = 4
+)
+
+// SessionFiles returns the session files saved in the profile holding the History database:
+// the Session_* and Tabs_* files under Sessions, or the Current/Last Session and Tabs files
+// older versions keep in the profile directory.
+func (cb *ChromeBrowser) SessionFiles(historyPath string) []string {
+	profileDir := filepath.Dir(historyPath)
+	var files []string
+	for _, pattern := range []string{
+		filepath.Join(profileDir, "Sessions", "Session_*"),
+		filepath.Join(profileDir, "Sessions", "Tabs_*"),
+		filepath.Join(profileDir, "* Session"),
+		filepath.Join(profileDir, "* Tabs"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				files = append(files, match)
+			}
+		}
+	}
+	return files
+}
+
+// ExtractSession reads the windows and tabs in a Chromium SNSS session file. Session files
+// hold the open windows and tabs, along with any closed while the session was written; tab
+// files hold the recently closed tabs and windows. The kind of file is told by the name of the
+// original sessionFile, as the copy at sessionPath is named differently.
+func (cb *ChromeBrowser) ExtractSession(ctx context.Context, sessionFile, sessionPath, profile string, verbose bool) (history.SessionEntry, error) {
+	commands, err := readSNSSFile(sessionPath)
+	if err != nil {
+		return history.SessionEntry{}, fmt.Errorf("failed to read Chromium session file %s: %v", sessionFile, err)
+	}
+
+	var entry history.SessionEntry
+	name := filepath.Base(sessionFile)
+	if strings.HasPrefix(name, "Tabs_") || strings.HasSuffix(name, " Tabs") {
+		entry, err = restoreTabsFromCommands(ctx, commands)
+	} else {
+		entry, err = restoreSessionFromCommands(ctx, commands)
+	}
+	if err != nil {
+		return history.SessionEntry{}, err
+	}
+	entry.Profile = profile
+	if info, err := os.Stat(sessionFile); err == nil {
+		entry.LastUpdate = info.ModTime()
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Retrieved %d windows and %d closed tabs from %s\n", len(entry.Windows), len(entry.ClosedTabs), sessionFile)
+	}
+	return entry, nil
+}
+
+// snssTab accumulates the state of one tab while replaying a command stream.
+type snssTab struct {
+	id            int32
+	windowID      int32
+	visualIndex   int32
+	selectedIndex int32
+	pinned        bool
+	lastActive    time.Time
+	closedAt      time.Time
+	navigations   map[int32]history.SessionNavigation
+}
+
+func newSNSSTab(id int32) *snssTab {
+	return &snssTab{id: id, selectedIndex: -1, navigations: map[int32]history.SessionNavigation{}}
+}
+
+// toSessionTab orders the tab's navigations by index and selects the current one.
+func (t *snssTab) toSessionTab() history.SessionTab {
+	indexes := make([]int32, 0, len(t.navigations))
+	for index := range t.navigations {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	tab := history.SessionTab{Pinned: t.pinned, LastAccessed: t.lastActive, ClosedAt: t.closedAt}
+	for i, index := range indexes {
+		tab.Navigations = append(tab.Navigations, t.navigations[index])
+		if index <= t.selectedIndex {
+			tab.CurrentIndex = i
+		}
+	}
+	if t.selectedIndex < 0 && len(indexes) > 0 {
+		tab.CurrentIndex = len(indexes) - 1
+	}
+	if len(tab.Navigations) > 0 {
+		tab.URL = tab.Navigations[tab.CurrentIndex].URL
+		tab.Title = tab.Navigations[tab.CurrentIndex].Title
+	}
+	return tab
+}
+
+// pruneNavigations removes count navigations starting at index and renumbers those after them.
+func (t *snssTab) pruneNavigations(index, count int32) {
+	pruned := map[int32]history.SessionNavigation{}
+	for i, navigation := range t.navigations {
+		switch {
+		case i < index:
+			pruned[i] = navigation
+		case i >= index+count:
+			pruned[i-count] = navigation
+		}
+	}
+	t.navigations = pruned
+	if t.selectedIndex >= index+count {
+		t.selectedIndex -= count
+	} else if t.selectedIndex >= index {
+		t.selectedIndex = index - 1
+	}
+}
+
+// parseSNSSNavigation decodes an UpdateTabNavigation payload: the tab id followed by a
+// serialized navigation entry. Fields after the title are optional in older files.
+func parseSNSSNavigation(payload []byte) (tabID, index int32, navigation history.SessionNavigation, ok bool) {
+	r := newPickleReader(payload)
+	tabID = r.int32()
+	index = r.int32()
+	navigation.URL = r.string()
+	navigation.Title = r.string16()
+	if r.err != nil {
+		return 0, 0, navigation, false
+	}
+
+	r.string() // Encoded page state.
+	r.int32()  // Transition type.
+	r.int32()  // Type mask.
+	r.string() // Referrer URL.
+	r.int32()  // Referrer policy.
+	r.string() // Original request URL.
+	r.int32()  // Is overriding user agent.
+	if timestamp := r.int64(); r.err == nil && timestamp != 0 {
+		navigation.Timestamp = ChromeTimeToTime(timestamp)
+	}
+	return tabID, index, navigation, true
+}
+
+// chromeSessionTime converts a session command's Chrome timestamp, zero when unset.
+func chromeSessionTime(timestamp int64) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return ChromeTimeToTime(timestamp)
+}
+
+// restoreSessionFromCommands replays a Session_* command stream into its windows and tabs.
+// Tabs and windows closed during the session are kept as closed rather than dropped.
+func restoreSessionFromCommands(ctx context.Context, commands []snssCommand) (history.SessionEntry, error) {
+	tabs := map[int32]*snssTab{}
+	tab := func(id int32) *snssTab {
+		if tabs[id] == nil {
+			tabs[id] = newSNSSTab(id)
+		}
+		return tabs[id]
+	}
+	windowClosed := map[int32]time.Time{}
+	windowIDs := map[int32]bool{}
+
+	for _, command := range commands {
+		if err := ctx.Err(); err != nil {
+			return history.SessionEntry{}, err
+		}
+		p := command.payload
+		switch command.id {
+		case snssSetTabWindow:
+			windowIDs[snssInt32(p, 0)] = true
+			tab(snssInt32(p, 4)).windowID = snssInt32(p, 0)
+		case snssSetTabIndexInWindow:
+			tab(snssInt32(p, 0)).visualIndex = snssInt32(p, 4)
+		case snssTabNavigationPathPrunedFromBack:
+			// Keeps only the navigations before the given index.
+			index := snssInt32(p, 4)
+			tab(snssInt32(p, 0)).pruneNavigations(index, math.MaxInt32-index)
+		case snssUpdateTabNavigation:
+			if tabID, index, navigation, ok := parseSNSSNavigation(p); ok {
+				tab(tabID).navigations[index] = navigation
+			}
+		case snssSetSelectedNavigationIndex:
+			tab(snssInt32(p, 0)).selectedIndex = snssInt32(p, 4)
+		case snssTabNavigationPathPrunedFromFront:
+			tab(snssInt32(p, 0)).pruneNavigations(0, snssInt32(p, 4))
+		case snssSetPinnedState:
+			tab(snssInt32(p, 0)).pinned = len(p) > 4 && p[4] != 0
+		case snssTabClosed:
+			tab(snssInt32(p, 0)).closedAt = chromeSessionTime(snssInt64(p, 8))
+		case snssWindowClosed:
+			windowClosed[snssInt32(p, 0)] = chromeSessionTime(snssInt64(p, 8))
+		case snssLastActiveTime:
+			tab(snssInt32(p, 0)).lastActive = chromeSessionTime(snssInt64(p, 8))
+		case snssTabNavigationPathPruned:
+			tab(snssInt32(p, 0)).pruneNavigations(snssInt32(p, 4), snssInt32(p, 8))
+		}
+	}
+
+	ordered := make([]*snssTab, 0, len(tabs))
+	for _, t := range tabs {
+		ordered = append(ordered, t)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].visualIndex != ordered[j].visualIndex {
+			return ordered[i].visualIndex < ordered[j].visualIndex
+		}
+		return ordered[i].id < ordered[j].id
+	})
+	windows := make([]int32, 0, len(windowIDs))
+	for id := range windowIDs {
+		windows = append(windows, id)
+	}
+	slices.Sort(windows)
+
+	var entry history.SessionEntry
+	for _, windowID := range windows {
+		window := history.SessionWindow{ClosedAt: windowClosed[windowID]}
+		for _, t := range ordered {
+			if t.windowID != windowID || len(t.navigations) == 0 {
+				continue
+			}
+			if t.closedAt.IsZero() {
+				window.Tabs = append(window.Tabs, t.toSessionTab())
+			} else {
+				window.ClosedTabs = append(window.ClosedTabs, t.toSessionTab())
+			}
+		}
+		if len(window.Tabs) == 0 && len(window.ClosedTabs) == 0 {
+			continue
+		}
+		if window.ClosedAt.IsZero() {
+			entry.Windows = append(entry.Windows, window)
+		} else {
+			entry.ClosedWindows = append(entry.ClosedWindows, window)
+		}
+	}
+	return entry, nil
+}
+
+// restoreTabsFromCommands replays a Tabs_* command stream into the recently closed windows and
+// tabs, leaving out entries that were restored since.
+func restoreTabsFromCommands(ctx context.Context, commands []snssCommand) (history.SessionEntry, error) {
+	type restoreEntry struct {
+		id       int32
+		closedAt time.Time
+		tabs     []*snssTab // A closed window's tabs, or the single closed tab.
+		window   bool
+	}
+	var entries []*restoreEntry
+	var current *snssTab
+	var window *restoreEntry
+	remainingTabs := int32(0)
+
+	for _, command := range commands {
+		if err := ctx.Err(); err != nil {
+			return history.SessionEntry{}, err
+		}
+		p := command.payload
+		switch command.id {
+		case snssRestoreWindow:
+			var id, tabCount int32
+			var timestamp int64
+			if isPickle(p) {
+				r := newPickleReader(p)
+				id = r.int32()
+				r.int32() // Selected tab index.
+				tabCount = r.int32()
+				timestamp = r.int64()
+			} else {
+				id, tabCount, timestamp = snssInt32(p, 0), snssInt32(p, 8), snssInt64(p, 16)
+			}
+			window = &restoreEntry{id: id, closedAt: chromeSessionTime(timestamp), window: true}
+			entries = append(entries, window)
+			remainingTabs = tabCount
+		case snssRestoreSelectedNavigationInTab:
+			current = newSNSSTab(snssInt32(p, 0))
+			current.selectedIndex = snssInt32(p, 4)
+			current.closedAt = chromeSessionTime(snssInt64(p, 8))
+			if window != nil && remainingTabs > 0 {
+				window.tabs = append(window.tabs, current)
+				remainingTabs--
+			} else {
+				window = nil
+				entries = append(entries, &restoreEntry{id: current.id, closedAt: current.closedAt, tabs: []*snssTab{current}})
+			}
+		case snssRestoreUpdateTabNavigation:
+			if _, index, navigation, ok := parseSNSSNavigation(p); ok && current != nil {
+				current.navigations[index] = navigation
+			}
+		case snssRestoreRestoredEntry:
+			id := snssInt32(p, 0)
+			entries = slices.DeleteFunc(entries, func(e *restoreEntry) bool { return e.id == id })
+		}
+	}
+
+	var entry history.SessionEntry
+	for _, e := range entries {
+		if !e.window {
+			if len(e.tabs[0].navigations) > 0 {
+				entry.ClosedTabs = append(entry.ClosedTabs, e.tabs[0].toSessionTab())
+			}
+			continue
+		}
+		window := history.SessionWindow{ClosedAt: e.closedAt}
+		for _, t := range e.tabs {
+			if len(t.navigations) > 0 {
+				window.Tabs = append(window.Tabs, t.toSessionTab())
+			}
+		}
+		entry.ClosedWindows = append(entry.ClosedWindows, window)
+	}
+	return entry, nil
+}
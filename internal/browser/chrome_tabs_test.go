@@ -0,0 +1,213 @@
+package browser
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// snssPickle builds a base::Pickle payload from int32, int64 and string fields.
+type snssPickle []byte
+
+func (p snssPickle) int32(v int32) snssPickle { return binary.LittleEndian.AppendUint32(p, uint32(v)) }
+func (p snssPickle) int64(v int64) snssPickle { return binary.LittleEndian.AppendUint64(p, uint64(v)) }
+
+func (p snssPickle) string(s string) snssPickle {
+	p = p.int32(int32(len(s)))
+	p = append(p, s...)
+	for len(p)%4 != 0 {
+		p = append(p, 0)
+	}
+	return p
+}
+
+func (p snssPickle) string16(s string) snssPickle {
+	units := utf16.Encode([]rune(s))
+	p = p.int32(int32(len(units)))
+	for _, unit := range units {
+		p = binary.LittleEndian.AppendUint16(p, unit)
+	}
+	for len(p)%4 != 0 {
+		p = append(p, 0)
+	}
+	return p
+}
+
+// payload prefixes the pickle with its size header.
+func (p snssPickle) payload() []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(p))), p...)
+}
+
+// snssNavigation builds an UpdateTabNavigation payload.
+func snssNavigation(tabID, index int32, url, title string, visited time.Time) []byte {
+	return snssPickle{}.int32(tabID).int32(index).string(url).string16(title).
+		string("").int32(0).int32(0).string("").int32(0).string(url).int32(0).
+		int64(TimeToChromeTime(visited)).payload()
+}
+
+// snssInts builds a fixed-layout payload of 32-bit fields.
+func snssInts(values ...int32) []byte {
+	var payload []byte
+	for _, v := range values {
+		payload = binary.LittleEndian.AppendUint32(payload, uint32(v))
+	}
+	return payload
+}
+
+// snssIDAndTime builds a fixed-layout payload of an id, a second 32-bit field and a Chrome
+// timestamp at offset 8.
+func snssIDAndTime(id, value int32, t time.Time) []byte {
+	return binary.LittleEndian.AppendUint64(snssInts(id, value), uint64(TimeToChromeTime(t)))
+}
+
+// writeSNSSFile writes a version 1 SNSS file holding the given commands.
+func writeSNSSFile(t *testing.T, path string, commands ...snssCommand) {
+	t.Helper()
+	data := append([]byte("SNSS"), 1, 0, 0, 0)
+	for _, command := range commands {
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(command.payload)+1))
+		data = append(data, command.id)
+		data = append(data, command.payload...)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestChromeBrowser_ExtractSession(t *testing.T) {
+	profileDir := t.TempDir()
+	sessionsDir := filepath.Join(profileDir, "Sessions")
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	visited := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	sessionPath := filepath.Join(sessionsDir, "Session_13388000000000000")
+	writeSNSSFile(t, sessionPath,
+		snssCommand{snssSetTabWindow, snssInts(1, 10)},
+		snssCommand{snssSetTabWindow, snssInts(1, 11)},
+		snssCommand{snssSetTabWindow, snssInts(1, 12)},
+		snssCommand{snssSetTabIndexInWindow, snssInts(10, 1)},
+		snssCommand{snssSetTabIndexInWindow, snssInts(11, 0)},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(10, 0, "https://go.dev/", "Go", visited)},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(10, 1, "https://go.dev/doc/", "Docs", visited.Add(time.Minute))},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(10, 2, "https://go.dev/blog/", "Blog", visited.Add(2*time.Minute))},
+		snssCommand{snssSetSelectedNavigationIndex, snssInts(10, 1)},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(11, 0, "https://chromium.org/", "Chromium", visited)},
+		snssCommand{snssSetPinnedState, []byte{11, 0, 0, 0, 1, 0, 0, 0}},
+		snssCommand{snssLastActiveTime, snssIDAndTime(11, 0, visited.Add(time.Hour))},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(12, 0, "https://closed.example.com/", "Closed", visited)},
+		snssCommand{snssTabClosed, snssIDAndTime(12, 0, visited.Add(2*time.Hour))},
+		snssCommand{snssTabNavigationPathPrunedFromBack, snssInts(10, 2)},
+		snssCommand{snssSetTabWindow, snssInts(2, 20)},
+		snssCommand{snssUpdateTabNavigation, snssNavigation(20, 0, "https://old.example.com/", "Old", visited)},
+		snssCommand{snssWindowClosed, snssIDAndTime(2, 0, visited.Add(3*time.Hour))},
+	)
+	tabsPath := filepath.Join(sessionsDir, "Tabs_13388000000000000")
+	writeSNSSFile(t, tabsPath,
+		snssCommand{snssRestoreSelectedNavigationInTab, snssIDAndTime(30, 0, visited.Add(4*time.Hour))},
+		snssCommand{snssRestoreUpdateTabNavigation, snssNavigation(30, 0, "https://restored.example.com/", "Restored", visited)},
+		snssCommand{snssRestoreWindow, append(snssInts(40, 0, 1, 0), binary.LittleEndian.AppendUint64(nil, uint64(TimeToChromeTime(visited.Add(5*time.Hour))))...)},
+		snssCommand{snssRestoreSelectedNavigationInTab, snssIDAndTime(41, 0, visited.Add(5*time.Hour))},
+		snssCommand{snssRestoreUpdateTabNavigation, snssNavigation(41, 0, "https://window.example.com/", "Window tab", visited)},
+		snssCommand{snssRestoreSelectedNavigationInTab, snssIDAndTime(50, 1, visited.Add(6*time.Hour))},
+		snssCommand{snssRestoreUpdateTabNavigation, snssNavigation(50, 0, "https://tab.example.com/a", "A", visited)},
+		snssCommand{snssRestoreUpdateTabNavigation, snssNavigation(50, 1, "https://tab.example.com/b", "B", visited)},
+		snssCommand{snssRestoreRestoredEntry, snssInts(30)},
+	)
+
+	cb := &ChromeBrowser{}
+	files := cb.SessionFiles(filepath.Join(profileDir, "History"))
+	if len(files) != 2 || files[0] != sessionPath || files[1] != tabsPath {
+		t.Fatalf("Expected [%s %s], got %v", sessionPath, tabsPath, files)
+	}
+
+	session, err := cb.ExtractSession(context.Background(), sessionPath, sessionPath, "Person 1", false)
+	if err != nil {
+		t.Fatalf("ExtractSession failed: %v", err)
+	}
+	if session.Profile != "Person 1" || len(session.Windows) != 1 || len(session.ClosedWindows) != 1 {
+		t.Fatalf("Unexpected session %+v", session)
+	}
+	window := session.Windows[0]
+	if len(window.Tabs) != 2 || len(window.ClosedTabs) != 1 {
+		t.Fatalf("Unexpected window %+v", window)
+	}
+	pinned := window.Tabs[0]
+	if pinned.URL != "https://chromium.org/" || !pinned.Pinned || !pinned.LastAccessed.Equal(visited.Add(time.Hour)) {
+		t.Errorf("Unexpected first tab %+v", pinned)
+	}
+	docs := window.Tabs[1]
+	if docs.URL != "https://go.dev/doc/" || docs.Title != "Docs" || docs.CurrentIndex != 1 || len(docs.Navigations) != 2 ||
+		!docs.Navigations[1].Timestamp.Equal(visited.Add(time.Minute)) {
+		t.Errorf("Unexpected second tab %+v", docs)
+	}
+	if closed := window.ClosedTabs[0]; closed.URL != "https://closed.example.com/" || !closed.ClosedAt.Equal(visited.Add(2*time.Hour)) {
+		t.Errorf("Unexpected closed tab %+v", closed)
+	}
+	if closedWindow := session.ClosedWindows[0]; !closedWindow.ClosedAt.Equal(visited.Add(3*time.Hour)) || closedWindow.Tabs[0].Title != "Old" {
+		t.Errorf("Unexpected closed window %+v", closedWindow)
+	}
+
+	tabs, err := cb.ExtractSession(context.Background(), tabsPath, tabsPath, "Person 1", false)
+	if err != nil {
+		t.Fatalf("ExtractSession failed: %v", err)
+	}
+	if len(tabs.Windows) != 0 || len(tabs.ClosedWindows) != 1 || len(tabs.ClosedTabs) != 1 {
+		t.Fatalf("Unexpected closed tabs session %+v", tabs)
+	}
+	if w := tabs.ClosedWindows[0]; !w.ClosedAt.Equal(visited.Add(5*time.Hour)) || len(w.Tabs) != 1 || w.Tabs[0].URL != "https://window.example.com/" {
+		t.Errorf("Unexpected closed window %+v", w)
+	}
+	if tab := tabs.ClosedTabs[0]; tab.URL != "https://tab.example.com/b" || tab.CurrentIndex != 1 || !tab.ClosedAt.Equal(visited.Add(6*time.Hour)) {
+		t.Errorf("Unexpected closed tab %+v", tab)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, path := range files {
+		if _, err := cb.ExtractSession(ctx, path, path, "Person 1", false); err != context.Canceled {
+			t.Errorf("Expected a cancelled context to stop replaying %s, got %v", path, err)
+		}
+	}
+}
+
+func TestReadSNSSFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("TruncatedCommand", func(t *testing.T) {
+		path := filepath.Join(dir, "Session_1")
+		writeSNSSFile(t, path, snssCommand{snssSetTabWindow, snssInts(1, 2)})
+		data, _ := os.ReadFile(path)
+		if err := os.WriteFile(path, append(data, 9, 0, snssSetTabWindow), 0644); err != nil {
+			t.Fatal(err)
+		}
+		commands, err := readSNSSFile(path)
+		if err != nil || len(commands) != 1 {
+			t.Errorf("Expected the complete command only, got %v, %v", commands, err)
+		}
+	})
+
+	t.Run("Encrypted", func(t *testing.T) {
+		path := filepath.Join(dir, "Session_2")
+		if err := os.WriteFile(path, []byte("SNSS\x02\x00\x00\x00"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readSNSSFile(path); err == nil {
+			t.Error("Expected an error for an encrypted session file")
+		}
+	})
+
+	t.Run("NotSNSS", func(t *testing.T) {
+		path := filepath.Join(dir, "Session_3")
+		if err := os.WriteFile(path, []byte("garbage"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readSNSSFile(path); err == nil {
+			t.Error("Expected an error for a file without the SNSS header")
+		}
+	})
+}
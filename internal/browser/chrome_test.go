@@ -1,6 +1,7 @@
 package browser
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
@@ -22,6 +23,8 @@ func TestNewChromeBrowser(t *testing.T) {
 func TestChromeBrowser_GetHistoryPath(t *testing.T) {
 	cb := &ChromeBrowser{}
 	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("LOCALAPPDATA", tempDir)
 
 	var baseDir string
 	switch runtime.GOOS {
@@ -47,6 +50,7 @@ func TestChromeBrowser_GetHistoryPath(t *testing.T) {
 	historyPaths := history.HistoryPathEntry{
 		Profile: "Default",
 		Path:    historyPath,
+		Channel: "stable",
 	}
 	if err != nil {
 		t.Fatalf("Failed to create History file: %v", err)
@@ -61,7 +65,27 @@ func TestChromeBrowser_GetHistoryPath(t *testing.T) {
 		t.Error("Expected at least one path, got none")
 	}
 	if paths[0] != historyPaths {
-		t.Errorf("Expected path %s, got %s", historyPath, paths[0])
+		t.Errorf("Expected path %s, got %s", historyPath, paths[0].Path)
+	}
+}
+
+func TestChromeBrowser_GetHistoryPathsFrom(t *testing.T) {
+	userDataDir := t.TempDir()
+	for _, profile := range []string{"Default", "Profile 2", "Crashpad"} {
+		if err := os.MkdirAll(filepath.Join(userDataDir, profile), 0755); err != nil {
+			t.Fatalf("Failed to create profile dir: %v", err)
+		}
+	}
+
+	paths, err := NewChromeBrowser().(DirectoryBrowser).GetHistoryPathsFrom(userDataDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 profiles, got %v", paths)
+	}
+	if paths[1].Path != filepath.Join(userDataDir, "Profile 2", "History") {
+		t.Errorf("Unexpected path %s", paths[1].Path)
 	}
 }
 
@@ -89,14 +113,16 @@ func TestChromeBrowser_ExtractHistory(t *testing.T) {
             id INTEGER PRIMARY KEY,
             url INTEGER,
             visit_time INTEGER,
-            transition INTEGER
+            transition INTEGER,
+            visit_duration INTEGER DEFAULT 0,
+            from_visit INTEGER DEFAULT 0
         );
         INSERT INTO urls VALUES 
             (1, 'https://test.com', 'Test', 2, 1, ?),
             (2, 'https://example.com', 'Example', 1, 0, ?);
         INSERT INTO visits VALUES 
-            (1, 1, ?, 1),
-            (2, 2, ?, 8);
+            (1, 1, ?, 1, 95000000, 0),
+            (2, 2, ?, 8, 0, 1);
     `, TimeToChromeTime(time.Now().Add(-1*time.Hour)), TimeToChromeTime(time.Now()),
 		TimeToChromeTime(time.Now().Add(-1*time.Hour)), TimeToChromeTime(time.Now()))
 	if err != nil {
@@ -107,7 +133,7 @@ func TestChromeBrowser_ExtractHistory(t *testing.T) {
 	startTime := time.Now().Add(-2 * time.Hour)
 	endTime := time.Now().Add(time.Hour)
 
-	entries, err := cb.ExtractHistory(dbPath, profile, startTime, endTime, false)
+	entries, err := history.Collect(cb.ExtractHistory(context.Background(), dbPath, profile, startTime, endTime, false))
 	if err != nil {
 		t.Fatalf("ExtractHistory failed: %v", err)
 	}
@@ -124,6 +150,52 @@ func TestChromeBrowser_ExtractHistory(t *testing.T) {
 			t.Errorf("Timestamp %v outside of range %v - %v", entry.Timestamp, startTime, endTime)
 		}
 	}
+	if len(entries) == 2 && (entries[1].Duration != 95*time.Second || entries[0].Duration != 0) {
+		t.Errorf("Expected visit durations 0s and 1m35s, got %v and %v", entries[0].Duration, entries[1].Duration)
+	}
+	if len(entries) == 2 && (entries[0].VisitID != 2 || entries[0].ReferrerVisitID != 1 || entries[0].ReferrerURL != "https://test.com" ||
+		entries[1].ReferrerVisitID != 0 || entries[1].ReferrerURL != "") {
+		t.Errorf("Unexpected referrers %+v", entries)
+	}
+}
+
+func TestChromeBrowser_ExtractHistoryOpener(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	visited := time.Now().Add(-time.Hour)
+	_, err = db.Exec(`
+        CREATE TABLE urls (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, typed_count INTEGER, last_visit_time INTEGER);
+        CREATE TABLE visits (id INTEGER PRIMARY KEY, url INTEGER, visit_time INTEGER, from_visit INTEGER, transition INTEGER,
+            visit_duration INTEGER, opener_visit INTEGER);
+        INSERT INTO urls VALUES (1, 'https://news.example.com/', 'News', 1, 1, ?);
+        INSERT INTO urls VALUES (2, 'https://story.example.com/', 'Story', 1, 0, ?);
+        INSERT INTO visits VALUES (10, 1, ?, 0, 1, 0, 0);
+        INSERT INTO visits VALUES (11, 2, ?, 0, 0, 0, 10);
+    `, TimeToChromeTime(visited), TimeToChromeTime(visited.Add(time.Minute)),
+		TimeToChromeTime(visited), TimeToChromeTime(visited.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	cb := &ChromeBrowser{}
+	entries, err := history.Collect(cb.ExtractHistory(context.Background(), dbPath, "Default", visited.Add(-time.Hour), time.Now(), false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].VisitID != 11 || entries[0].ReferrerVisitID != 10 || entries[0].ReferrerURL != "https://news.example.com/" {
+		t.Errorf("Expected the new tab visit to be referred by its opener, got %+v", entries)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := history.Collect(cb.ExtractHistory(ctx, dbPath, "Default", visited.Add(-time.Hour), time.Now(), false)); err == nil {
+		t.Error("Expected a cancelled context to stop the query")
+	}
 }
 
 func TestTimeConversions(t *testing.T) {
@@ -0,0 +1,101 @@
+package browser
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// chromeCoreTransitions names the core page transition types stored in the low byte of
+// visits.transition, indexed by value.
+var chromeCoreTransitions = []string{
+	"LINK",
+	"TYPED",
+	"AUTO_BOOKMARK",
+	"AUTO_SUBFRAME",
+	"MANUAL_SUBFRAME",
+	"GENERATED",
+	"AUTO_TOPLEVEL",
+	"FORM_SUBMIT",
+	"RELOAD",
+	"KEYWORD",
+	"KEYWORD_GENERATED",
+}
+
+// chromeTransitionQualifiers lists the qualifier flags of visits.transition in bit order.
+var chromeTransitionQualifiers = []struct {
+	mask uint32
+	name string
+}{
+	{0x00800000, "BLOCKED"},
+	{0x01000000, "FORWARD_BACK"},
+	{0x02000000, "FROM_ADDRESS_BAR"},
+	{0x04000000, "HOME_PAGE"},
+	{0x08000000, "FROM_API"},
+	{0x10000000, "CHAIN_START"},
+	{0x20000000, "CHAIN_END"},
+	{0x40000000, "CLIENT_REDIRECT"},
+	{0x80000000, "SERVER_REDIRECT"},
+}
+
+const (
+	chromeCoreMask       = 0x000000FF
+	chromeQualifierMask  = 0xFFFFFF00
+	chromeIsRedirectMask = 0xC0000000 // Set when the visit was a client or server redirect.
+)
+
+// DecodeChromeTransition decodes a Chromium visits.transition value. Chrome stores the value as
+// a signed 32-bit integer, so transitions carrying SERVER_REDIRECT read back negative.
+func DecodeChromeTransition(transition int64) history.PageTransition {
+	bits := uint32(transition)
+	decoded := history.PageTransition{Raw: int64(bits), Qualifiers: []string{}}
+
+	if core := bits & chromeCoreMask; int(core) < len(chromeCoreTransitions) {
+		decoded.Core = chromeCoreTransitions[core]
+	} else {
+		decoded.Core = fmt.Sprintf("UNKNOWN_CORE (%d)", core)
+	}
+
+	known := uint32(0)
+	for _, qualifier := range chromeTransitionQualifiers {
+		known |= qualifier.mask
+		if bits&qualifier.mask != 0 {
+			decoded.Qualifiers = append(decoded.Qualifiers, qualifier.name)
+		}
+	}
+	if bits&chromeIsRedirectMask != 0 {
+		decoded.Qualifiers = append(decoded.Qualifiers, "IS_REDIRECT_MASK")
+	}
+	if unknown := bits & chromeQualifierMask &^ known; unknown != 0 {
+		decoded.Qualifiers = append(decoded.Qualifiers, fmt.Sprintf("UNKNOWN_QUALIFIER (0x%08X)", unknown))
+	}
+	return decoded
+}
+
+// chromeVisitTypes maps Chromium core transition types to normalized visit types.
+var chromeVisitTypes = map[string]history.VisitType{
+	"LINK":              history.VisitLink,
+	"TYPED":             history.VisitTyped,
+	"AUTO_BOOKMARK":     history.VisitBookmark,
+	"AUTO_SUBFRAME":     history.VisitEmbed,
+	"MANUAL_SUBFRAME":   history.VisitEmbed,
+	"GENERATED":         history.VisitGenerated,
+	"AUTO_TOPLEVEL":     history.VisitLink,
+	"FORM_SUBMIT":       history.VisitFormSubmit,
+	"RELOAD":            history.VisitReload,
+	"KEYWORD":           history.VisitKeyword,
+	"KEYWORD_GENERATED": history.VisitGenerated,
+}
+
+// chromeVisitType normalizes a decoded transition. Chromium does not record whether a redirect
+// was permanent, so every redirect is reported as temporary.
+func chromeVisitType(transition history.PageTransition) history.VisitType {
+	if slices.Contains(transition.Qualifiers, "IS_REDIRECT_MASK") {
+		return history.VisitRedirectTemporary
+	}
+	if visitType, ok := chromeVisitTypes[transition.Core]; ok {
+		return visitType
+	}
+	return history.VisitUnknown
+}
@@ -0,0 +1,59 @@
+package browser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+func TestDecodeChromeTransition(t *testing.T) {
+	tests := []struct {
+		transition int64
+		raw        int64
+		core       string
+		qualifiers []string
+		str        string
+	}{
+		{0, 0, "LINK", []string{}, "LINK"},
+		{1, 1, "TYPED", []string{}, "TYPED"},
+		{0x30000000 | 8, 0x30000008, "RELOAD", []string{"CHAIN_START", "CHAIN_END"}, "RELOAD (CHAIN_START, CHAIN_END)"},
+		// CHAIN_END | SERVER_REDIRECT | FROM_ADDRESS_BAR | TYPED, as Chrome stores it: a negative int32.
+		{int64(int32(-0x80000000 + 0x22000001)), 0xA2000001, "TYPED",
+			[]string{"FROM_ADDRESS_BAR", "CHAIN_END", "SERVER_REDIRECT", "IS_REDIRECT_MASK"},
+			"TYPED (FROM_ADDRESS_BAR, CHAIN_END, SERVER_REDIRECT, IS_REDIRECT_MASK)"},
+		{0x0C000000 | 6, 0x0C000006, "AUTO_TOPLEVEL", []string{"HOME_PAGE", "FROM_API"}, "AUTO_TOPLEVEL (HOME_PAGE, FROM_API)"},
+		{0x40000000, 0x40000000, "LINK", []string{"CLIENT_REDIRECT", "IS_REDIRECT_MASK"}, "LINK (CLIENT_REDIRECT, IS_REDIRECT_MASK)"},
+		{0x00400000 | 42, 0x0040002A, "UNKNOWN_CORE (42)", []string{"UNKNOWN_QUALIFIER (0x00400000)"}, "UNKNOWN_CORE (42) (UNKNOWN_QUALIFIER (0x00400000))"},
+	}
+	for _, tt := range tests {
+		got := DecodeChromeTransition(tt.transition)
+		if got.Raw != tt.raw || got.Core != tt.core || !slices.Equal(got.Qualifiers, tt.qualifiers) || got.String() != tt.str {
+			t.Errorf("DecodeChromeTransition(%d) = %+v (%q), want raw %d, core %s, qualifiers %v (%q)",
+				tt.transition, got, got.String(), tt.raw, tt.core, tt.qualifiers, tt.str)
+		}
+	}
+}
+
+func TestChromeVisitType(t *testing.T) {
+	tests := []struct {
+		transition int64
+		want       history.VisitType
+	}{
+		{0, history.VisitLink},
+		{1 | 0x02000000, history.VisitTyped},
+		{2, history.VisitBookmark},
+		{3, history.VisitEmbed},
+		{5, history.VisitGenerated},
+		{7, history.VisitFormSubmit},
+		{8, history.VisitReload},
+		{9, history.VisitKeyword},
+		{int64(int32(-0x80000000 + 0x20000001)), history.VisitRedirectTemporary},
+		{42, history.VisitUnknown},
+	}
+	for _, tt := range tests {
+		if got := chromeVisitType(DecodeChromeTransition(tt.transition)); got != tt.want {
+			t.Errorf("chromeVisitType(%d) = %q, want %q", tt.transition, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ChromiumBrowser implements the Browser interface for any browser sharing Chrome's profile layout and history schema.
+type ChromiumBrowser struct {
+	ChromeBrowser
+	Descriptor Descriptor
+}
+
+// NewChromiumBrowser creates a new ChromiumBrowser from the given descriptor.
+func NewChromiumBrowser(d Descriptor) Browser {
+	return &ChromiumBrowser{Descriptor: d}
+}
+
+// GetHistoryPaths retrieves the history database paths from every user data directory candidate for the current OS.
+func (cb *ChromiumBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return cb.GetHistoryPathsIn(CurrentEnvironment())
+}
+
+// GetHistoryPathsIn retrieves the history database paths from every user data directory candidate in env.
+func (cb *ChromiumBrowser) GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error) {
+	return cb.Descriptor.historyPaths(env, cb.getPaths)
+}
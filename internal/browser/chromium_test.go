@@ -0,0 +1,236 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+func TestNewChromiumBrowser(t *testing.T) {
+	browser := NewChromiumBrowser(Descriptor{Name: "test"})
+	cb, ok := browser.(*ChromiumBrowser)
+	if !ok {
+		t.Fatal("NewChromiumBrowser should return a *ChromiumBrowser")
+	}
+	if cb.Descriptor.Name != "test" {
+		t.Errorf("Expected descriptor name 'test', got %q", cb.Descriptor.Name)
+	}
+}
+
+func TestChromiumBrowser_GetHistoryPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		browser string
+		dirs    map[string][]string
+	}{
+		{
+			name:    "Edge",
+			browser: "edge",
+			dirs: map[string][]string{
+				"windows": {"Microsoft", "Edge", "User Data"},
+				"darwin":  {"Library", "Application Support", "Microsoft Edge"},
+				"linux":   {".config", "microsoft-edge"},
+			},
+		},
+		{
+			name:    "Brave",
+			browser: "brave",
+			dirs: map[string][]string{
+				"windows": {"BraveSoftware", "Brave-Browser", "User Data"},
+				"darwin":  {"Library", "Application Support", "BraveSoftware", "Brave-Browser"},
+				"linux":   {".config", "BraveSoftware", "Brave-Browser"},
+			},
+		},
+		{
+			name:    "Vivaldi",
+			browser: "vivaldi",
+			dirs: map[string][]string{
+				"windows": {"Vivaldi", "User Data"},
+				"darwin":  {"Library", "Application Support", "Vivaldi"},
+				"linux":   {".config", "vivaldi"},
+			},
+		},
+		{
+			name:    "Yandex",
+			browser: "yandex",
+			dirs: map[string][]string{
+				"windows": {"Yandex", "YandexBrowser", "User Data"},
+				"darwin":  {"Library", "Application Support", "Yandex", "YandexBrowser"},
+				"linux":   {".config", "yandex-browser"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, setupProfile := range []bool{true, false} {
+			name := tt.name + "_WithProfile"
+			if !setupProfile {
+				name = tt.name + "_NoProfile"
+			}
+			t.Run(name, func(t *testing.T) {
+				tempDir := t.TempDir()
+				segments, ok := tt.dirs[runtime.GOOS]
+				if !ok {
+					t.Skipf("Skipping test on unsupported OS: %s", runtime.GOOS)
+				}
+				t.Setenv("HOME", tempDir)
+				t.Setenv("LOCALAPPDATA", tempDir)
+				t.Setenv("APPDATA", tempDir)
+				baseDir := filepath.Join(append([]string{tempDir}, segments...)...)
+
+				var historyPath string
+				if setupProfile {
+					defaultDir := filepath.Join(baseDir, "Default")
+					if err := os.MkdirAll(defaultDir, 0755); err != nil {
+						t.Fatalf("Failed to create default dir: %v", err)
+					}
+					historyPath = filepath.Join(defaultDir, "History")
+					if err := os.WriteFile(historyPath, nil, 0644); err != nil {
+						t.Fatalf("Failed to create History file: %v", err)
+					}
+				}
+
+				b := All()[tt.browser]
+				paths, err := b.GetHistoryPaths()
+				if !setupProfile {
+					if !os.IsNotExist(err) {
+						t.Errorf("Expected an error indicating path not exist, got %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				expected := history.HistoryPathEntry{Profile: "Default", Path: historyPath}
+				if len(paths) != 1 || paths[0] != expected {
+					t.Errorf("Expected [%v], got %v", expected, paths)
+				}
+			})
+		}
+	}
+}
+
+func TestChromiumBrowser_MultipleCandidates(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	for _, dir := range []string{"first", "second"} {
+		profileDir := filepath.Join(tempDir, dir, "Default")
+		if err := os.MkdirAll(profileDir, 0755); err != nil {
+			t.Fatalf("Failed to create profile dir: %v", err)
+		}
+	}
+
+	cb := NewChromiumBrowser(Descriptor{
+		Name: "test",
+		UserDataDirs: map[string][]string{
+			runtime.GOOS: {"$HOME/first", "$HOME/missing", "$HOME/second"},
+		},
+	})
+	paths, err := cb.GetHistoryPaths()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d", len(paths))
+	}
+	if paths[1].Path != filepath.Join(tempDir, "second", "Default", "History") {
+		t.Errorf("Unexpected second path %s", paths[1].Path)
+	}
+}
+
+func TestChromiumBrowser_OperaSingleProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("APPDATA", tempDir)
+
+	var segments []string
+	switch runtime.GOOS {
+	case "windows":
+		segments = []string{"Opera Software", "Opera Stable"}
+	case "darwin":
+		segments = []string{"Library", "Application Support", "com.operasoftware.Opera"}
+	case "linux":
+		segments = []string{".config", "opera"}
+	default:
+		t.Skipf("Skipping test on unsupported OS: %s", runtime.GOOS)
+	}
+	baseDir := filepath.Join(append([]string{tempDir}, segments...)...)
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		t.Fatalf("Failed to create user data dir: %v", err)
+	}
+	historyPath := filepath.Join(baseDir, "History")
+	if err := os.WriteFile(historyPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create History file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "Preferences"), []byte(`{"profile":{"name":"Work"}}`), 0644); err != nil {
+		t.Fatalf("Failed to create Preferences file: %v", err)
+	}
+
+	paths, err := All()["opera"].GetHistoryPaths()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := history.HistoryPathEntry{Profile: "Default", ProfileName: "Work", Path: historyPath}
+	if len(paths) != 1 || paths[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, paths)
+	}
+}
+
+func TestChromiumBrowser_ChromeChannels(t *testing.T) {
+	tests := []struct {
+		browser string
+		channel string
+		dirs    map[string][]string
+	}{
+		{"chrome-beta", "beta", map[string][]string{
+			"windows": {"Google", "Chrome Beta", "User Data"},
+			"darwin":  {"Library", "Application Support", "Google", "Chrome Beta"},
+			"linux":   {".config", "google-chrome-beta"},
+		}},
+		{"chrome-dev", "dev", map[string][]string{
+			"windows": {"Google", "Chrome Dev", "User Data"},
+			"darwin":  {"Library", "Application Support", "Google", "Chrome Dev"},
+			"linux":   {".config", "google-chrome-unstable"},
+		}},
+		{"chrome-canary", "canary", map[string][]string{
+			"windows": {"Google", "Chrome SxS", "User Data"},
+			"darwin":  {"Library", "Application Support", "Google", "Chrome Canary"},
+			"linux":   {".config", "google-chrome-canary"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.browser, func(t *testing.T) {
+			segments, ok := tt.dirs[runtime.GOOS]
+			if !ok {
+				t.Skipf("Skipping test on unsupported OS: %s", runtime.GOOS)
+			}
+			tempDir := t.TempDir()
+			t.Setenv("HOME", tempDir)
+			t.Setenv("LOCALAPPDATA", tempDir)
+			profileDir := filepath.Join(append(append([]string{tempDir}, segments...), "Default")...)
+			if err := os.MkdirAll(profileDir, 0755); err != nil {
+				t.Fatalf("Failed to create profile dir: %v", err)
+			}
+
+			paths, err := All()[tt.browser].GetHistoryPaths()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := history.HistoryPathEntry{Profile: "Default", Path: filepath.Join(profileDir, "History"), Channel: tt.channel}
+			if len(paths) != 1 || paths[0] != expected {
+				t.Errorf("Expected [%v], got %v", expected, paths)
+			}
+		})
+	}
+}
+
+func TestChromiumBrowser_UnsupportedOS(t *testing.T) {
+	cb := NewChromiumBrowser(Descriptor{Name: "test"})
+	if _, err := cb.GetHistoryPaths(); err == nil {
+		t.Error("Expected an error for a descriptor without paths for this OS")
+	}
+}
@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"errors"
+	"os"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// Descriptor describes where a browser keeps its profile data on each operating system.
+type Descriptor struct {
+	// Name is the identifier used with --browser and in output entries.
+	Name string
+	// Channel is the release channel the descriptor covers, such as "stable" or "beta". It is
+	// recorded on every path and entry read through the descriptor.
+	Channel string
+	// UserDataDirs maps a GOOS value to candidate user data directories. Paths use forward
+	// slashes and may reference $HOME, $APPDATA, $LOCALAPPDATA or the XDG base directories.
+	UserDataDirs map[string][]string
+	// Flatpak lists Linux user data directory candidates for a Flatpak install, usually under
+	// $HOME/.var/app/<app-id>.
+	Flatpak []string
+	// Snap lists Linux user data directory candidates for a Snap install, usually under
+	// $HOME/snap/<name>.
+	Snap []string
+	// Executables maps a GOOS value to hints for the browser binary, either absolute paths
+	// or names looked up in PATH.
+	Executables map[string][]string
+}
+
+// Install variants reported in history.HistoryPathEntry.Variant. A native install has an empty variant.
+const (
+	VariantFlatpak = "flatpak"
+	VariantSnap    = "snap"
+)
+
+// userDataDir is an expanded user data directory candidate and the install variant it belongs to.
+type userDataDir struct {
+	Path    string
+	Variant string
+}
+
+// historyPaths runs getPaths over every user data directory candidate in env and merges
+// the results. When no candidate yields a profile it returns the last error, preferring one
+// other than a missing directory so that unreadable profiles are not reported as absent.
+func (d Descriptor) historyPaths(env Environment, getPaths func(dir string) ([]history.HistoryPathEntry, error)) ([]history.HistoryPathEntry, error) {
+	dirs, err := d.userDataDirs(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var profilePaths []history.HistoryPathEntry
+	lastErr := error(os.ErrNotExist)
+	for _, dir := range dirs {
+		paths, err := getPaths(dir.Path)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) || errors.Is(lastErr, os.ErrNotExist) {
+				lastErr = err
+			}
+			continue
+		}
+		for i := range paths {
+			paths[i].Variant = dir.Variant
+			paths[i].Channel = d.Channel
+			paths[i].User = env.User
+		}
+		profilePaths = append(profilePaths, paths...)
+	}
+	if len(profilePaths) == 0 {
+		return nil, lastErr
+	}
+	return profilePaths, nil
+}
+
+// userDataDirs expands the descriptor's candidate directories for env. On Linux the Snap
+// and Flatpak layouts are probed after the native ones.
+func (d Descriptor) userDataDirs(env Environment) ([]userDataDir, error) {
+	candidates, ok := d.UserDataDirs[env.OS]
+	if !ok {
+		return nil, unsupportedOSError(env.OS)
+	}
+	var dirs []userDataDir
+	for _, candidate := range candidates {
+		dirs = append(dirs, userDataDir{Path: env.expandPath(candidate)})
+	}
+	if env.OS == "linux" {
+		for _, candidate := range d.Snap {
+			dirs = append(dirs, userDataDir{Path: env.expandPath(candidate), Variant: VariantSnap})
+		}
+		for _, candidate := range d.Flatpak {
+			dirs = append(dirs, userDataDir{Path: env.expandPath(candidate), Variant: VariantFlatpak})
+		}
+	}
+	return dirs, nil
+}
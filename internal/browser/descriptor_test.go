@@ -0,0 +1,118 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-ini/ini"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+func TestDescriptor_SandboxedVariants(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("Snap and Flatpak layouts only apply to linux, running on %s", runtime.GOOS)
+	}
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	nativeDir := filepath.Join(tempDir, ".config", "chromium", "Default")
+	snapDir := filepath.Join(tempDir, "snap", "chromium", "common", "chromium", "Default")
+	flatpakDir := filepath.Join(tempDir, ".var", "app", "org.chromium.Chromium", "config", "chromium", "Profile 1")
+	for _, dir := range []string{nativeDir, snapDir, flatpakDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create profile dir: %v", err)
+		}
+	}
+
+	paths, err := All()["chromium"].GetHistoryPaths()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []history.HistoryPathEntry{
+		{Profile: "Default", Path: filepath.Join(nativeDir, "History")},
+		{Profile: "Default", Path: filepath.Join(snapDir, "History"), Variant: VariantSnap},
+		{Profile: "Profile 1", Path: filepath.Join(flatpakDir, "History"), Variant: VariantFlatpak},
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %d paths, got %v", len(expected), paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("paths[%d] = %v, want %v", i, paths[i], expected[i])
+		}
+	}
+}
+
+func TestDescriptor_FirefoxSnap(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("Snap and Flatpak layouts only apply to linux, running on %s", runtime.GOOS)
+	}
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	baseDir := filepath.Join(tempDir, "snap", "firefox", "common", ".mozilla", "firefox")
+	if err := os.MkdirAll(filepath.Join(baseDir, "xyz.default"), 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	placesPath := filepath.Join(baseDir, "xyz.default", "places.sqlite")
+	if err := os.WriteFile(placesPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+	cfg := ini.Empty()
+	section, _ := cfg.NewSection("Profile0")
+	section.NewKey("Path", "xyz.default")
+	section.NewKey("IsRelative", "1")
+	cfg.SaveTo(filepath.Join(baseDir, "profiles.ini"))
+
+	paths, err := NewFirefoxBrowser().GetHistoryPaths()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := history.HistoryPathEntry{Profile: "Profile0", Path: placesPath, Variant: VariantSnap}
+	if len(paths) != 1 || paths[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, paths)
+	}
+}
+
+func TestDescriptor_SandboxedIgnoredOffLinux(t *testing.T) {
+	d := Descriptor{
+		UserDataDirs: map[string][]string{"darwin": {"/native"}},
+		Snap:         []string{"/snap"},
+		Flatpak:      []string{"/flatpak"},
+	}
+	dirs, err := d.userDataDirs(Environment{OS: "darwin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0].Variant != "" {
+		t.Errorf("Expected only the native candidate, got %v", dirs)
+	}
+}
+
+func TestDescriptor_HistoryPathsKeepsReadErrors(t *testing.T) {
+	d := Descriptor{UserDataDirs: map[string][]string{"linux": {"/unreadable", "/missing"}}}
+	denied := &os.PathError{Op: "open", Path: "/unreadable", Err: os.ErrPermission}
+	getPaths := func(dir string) ([]history.HistoryPathEntry, error) {
+		if dir == "/unreadable" {
+			return nil, denied
+		}
+		return nil, os.ErrNotExist
+	}
+
+	// A later missing directory must not hide why an earlier one could not be read.
+	_, err := d.historyPaths(Environment{OS: "linux"}, getPaths)
+	if !errors.Is(err, os.ErrPermission) {
+		t.Errorf("Expected the permission error, got %v", err)
+	}
+
+	d.UserDataDirs["linux"] = []string{"/missing"}
+	if _, err := d.historyPaths(Environment{OS: "linux"}, getPaths); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+	if _, err := d.historyPaths(Environment{OS: "plan9"}, getPaths); !errors.Is(err, ErrUnsupportedOS) {
+		t.Errorf("Expected an unsupported OS error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"path"
+	"strings"
+)
+
+// History database file names used by each browser family.
+const (
+	ChromiumHistoryFile    = "History"
+	GeckoHistoryFile       = "places.sqlite"
+	SafariHistoryFile      = "History.db"
+	EpiphanyHistoryFile    = "ephy-history.db"
+	FalkonHistoryFile      = "browsedata.db"
+	QutebrowserHistoryFile = "history.sqlite"
+)
+
+// DetectBrowser returns the registered browser whose user data directory layout matches
+// dbPath, a slash-separated path to a history database found outside the default locations,
+// such as inside an archive. When no layout matches it falls back to the first browser
+// registered for that database file name, e.g. "chrome" for History and "firefox" for
+// places.sqlite. ok is false when the file is not a known history database.
+func DetectBrowser(dbPath string) (name string, ok bool) {
+	file := path.Base(dbPath)
+	dir := "/" + strings.ToLower(path.Dir(dbPath)) + "/"
+	longest := 0
+	for _, r := range registrations() {
+		if r.descriptor == nil || r.historyFile != file {
+			continue
+		}
+		if !ok {
+			name, ok = r.name, true
+		}
+		for _, candidate := range r.descriptor.layouts() {
+			suffix := "/" + strings.ToLower(candidate) + "/"
+			if len(suffix) > longest && strings.Contains(dir, suffix) {
+				name, longest = r.name, len(suffix)
+			}
+		}
+	}
+	return name, ok
+}
+
+// layouts returns the descriptor's user data directories for every OS and install variant
+// with their leading variable reference removed, e.g. "Google/Chrome/User Data".
+func (d Descriptor) layouts() []string {
+	var candidates []string
+	for _, dirs := range d.UserDataDirs {
+		candidates = append(candidates, dirs...)
+	}
+	candidates = append(candidates, d.Snap...)
+	candidates = append(candidates, d.Flatpak...)
+
+	var layouts []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "$") {
+			_, candidate, _ = strings.Cut(candidate, "/")
+		}
+		if candidate != "" {
+			layouts = append(layouts, candidate)
+		}
+	}
+	return layouts
+}
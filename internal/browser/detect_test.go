@@ -0,0 +1,37 @@
+package browser
+
+import "testing"
+
+func TestDetectBrowser(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"alice/AppData/Local/Google/Chrome/User Data/Default/History", "chrome", true},
+		{"alice/AppData/Local/Google/Chrome Beta/User Data/Profile 1/History", "chrome-beta", true},
+		{"alice/AppData/Local/Microsoft/Edge/User Data/Default/History", "edge", true},
+		{"home/bob/.config/BraveSoftware/Brave-Browser/Default/History", "brave", true},
+		{"home/bob/snap/chromium/common/chromium/Default/History", "chromium", true},
+		{"Users/carol/AppData/Roaming/Opera Software/Opera Stable/History", "opera", true},
+		{"backup/Default/History", "chrome", true},
+		{"home/bob/.mozilla/firefox/abc.default/places.sqlite", "firefox", true},
+		{"Users/carol/Library/Application Support/librewolf/Profiles/x.default/places.sqlite", "librewolf", true},
+		{"copied/xyz.default-release/places.sqlite", "firefox", true},
+		{"Users/carol/Library/Safari/History.db", "safari", true},
+		{"evidence/History.db", "safari", true},
+		{"home/bob/.local/share/epiphany/ephy-history.db", "epiphany", true},
+		{"home/bob/.config/falkon/profiles/default/browsedata.db", "falkon", true},
+		{"home/bob/.local/share/qutebrowser/history.sqlite", "qutebrowser", true},
+		{"home/bob/.config/google-chrome/Default/Cookies", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name, ok := DetectBrowser(tt.path)
+			if ok != tt.ok || name != tt.expected {
+				t.Errorf("DetectBrowser(%q) = %q, %v; want %q, %v", tt.path, name, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment is the operating system and variable set that descriptor paths are resolved against.
+type Environment struct {
+	// OS is the GOOS value selecting which descriptor paths apply.
+	OS string
+	// User is the OS username the environment belongs to; empty for the running user.
+	User string
+	// Vars supplies values for $HOME, $APPDATA, $LOCALAPPDATA and similar references. When nil
+	// the running process environment is used.
+	Vars map[string]string
+	// Root is the directory a disk image is mounted at; empty for the running system.
+	Root string
+}
+
+// CurrentEnvironment returns the Environment of the running system.
+func CurrentEnvironment() Environment {
+	return Environment{OS: runtime.GOOS}
+}
+
+// RootedEnvironment returns an Environment for a user of a goos system whose disk is mounted
+// at root. home is the user's home directory as seen inside the image, e.g. "/home/alice" or
+// `C:\Users\alice`.
+func RootedEnvironment(root, goos, home string) (Environment, error) {
+	switch goos {
+	case "windows", "darwin", "linux":
+	default:
+		return Environment{}, unsupportedOSError(goos)
+	}
+	if home == "" {
+		return Environment{}, fmt.Errorf("a home directory inside %s is required", root)
+	}
+
+	homeDir := filepath.Join(root, filepath.FromSlash(imagePath(home)))
+	vars := map[string]string{
+		"HOME":        homeDir,
+		"USERPROFILE": homeDir,
+	}
+	if goos == "windows" {
+		vars["APPDATA"] = filepath.Join(homeDir, "AppData", "Roaming")
+		vars["LOCALAPPDATA"] = filepath.Join(homeDir, "AppData", "Local")
+	}
+	return Environment{OS: goos, User: filepath.Base(homeDir), Vars: vars, Root: root}, nil
+}
+
+// userHomeParents lists the directories holding user homes on each OS, relative to the system root.
+var userHomeParents = map[string]string{
+	"windows": "Users",
+	"darwin":  "Users",
+	"linux":   "home",
+}
+
+// nonUserHomes lists entries of the home parent directories that are not real user accounts.
+var nonUserHomes = map[string]bool{
+	"all users":          true,
+	"default":            true,
+	"default user":       true,
+	"defaultuser0":       true,
+	"public":             true,
+	"shared":             true,
+	"lost+found":         true,
+	"wdagutilityaccount": true,
+}
+
+// UserEnvironments returns an Environment for every user home on a goos system rooted at
+// root, e.g. /home/* or C:\Users\*. An empty root means the running system's own root. On
+// Linux the root account's /root home is included when present.
+func UserEnvironments(root, goos string) ([]Environment, error) {
+	parent, ok := userHomeParents[goos]
+	if !ok {
+		return nil, unsupportedOSError(goos)
+	}
+	imageRoot := root
+	if root == "" {
+		root = systemRoot(goos)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, parent))
+	if err != nil {
+		return nil, err
+	}
+	var homes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || nonUserHomes[strings.ToLower(name)] {
+			continue
+		}
+		homes = append(homes, parent+"/"+name)
+	}
+	if goos == "linux" {
+		if info, err := os.Stat(filepath.Join(root, "root")); err == nil && info.IsDir() {
+			homes = append(homes, "root")
+		}
+	}
+
+	var envs []Environment
+	for _, home := range homes {
+		env, err := RootedEnvironment(root, goos, home)
+		if err != nil {
+			return nil, err
+		}
+		env.Root = imageRoot
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+// systemRoot returns the filesystem root of the running system for goos.
+func systemRoot(goos string) string {
+	if goos == "windows" {
+		drive := os.Getenv("SystemDrive")
+		if drive == "" {
+			drive = "C:"
+		}
+		return drive + `\`
+	}
+	return "/"
+}
+
+// xdgDefaults gives the XDG base directories, relative to $HOME, used when the variable is unset.
+var xdgDefaults = map[string]string{
+	"XDG_CONFIG_HOME": ".config",
+	"XDG_DATA_HOME":   ".local/share",
+}
+
+// Getenv looks up a variable in the environment. Unset XDG base directory variables resolve
+// to their defaults under $HOME.
+func (e Environment) Getenv(key string) string {
+	value := e.Vars[key]
+	if e.Vars == nil {
+		value = os.Getenv(key)
+	}
+	if value == "" && xdgDefaults[key] != "" {
+		value = filepath.Join(e.Getenv("HOME"), filepath.FromSlash(xdgDefaults[key]))
+	}
+	return value
+}
+
+// imageFile rebases an absolute path recorded inside the environment's disk image, such as a
+// profile location saved in a settings file, onto the image root. Paths on the running system
+// are returned unchanged.
+func (e Environment) imageFile(path string) string {
+	if e.Root == "" {
+		return path
+	}
+	return filepath.Join(e.Root, filepath.FromSlash(imagePath(path)))
+}
+
+// expandPath resolves variable references in a slash-separated descriptor path.
+func (e Environment) expandPath(path string) string {
+	return filepath.FromSlash(os.Expand(path, e.Getenv))
+}
+
+// imagePath converts a path inside a disk image, possibly with a Windows drive letter and
+// backslashes, into a slash-separated path relative to the image root.
+func imagePath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) >= 2 && path[1] == ':' {
+		path = path[2:]
+	}
+	return strings.TrimPrefix(path, "/")
+}
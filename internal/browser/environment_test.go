@@ -0,0 +1,272 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-ini/ini"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+func TestRootedEnvironment(t *testing.T) {
+	root := filepath.Join("mnt", "evidence")
+	tests := []struct {
+		name     string
+		goos     string
+		home     string
+		expected map[string]string
+	}{
+		{
+			name: "WindowsDriveLetter",
+			goos: "windows",
+			home: `C:\Users\alice`,
+			expected: map[string]string{
+				"HOME":         filepath.Join(root, "Users", "alice"),
+				"APPDATA":      filepath.Join(root, "Users", "alice", "AppData", "Roaming"),
+				"LOCALAPPDATA": filepath.Join(root, "Users", "alice", "AppData", "Local"),
+			},
+		},
+		{
+			name:     "Darwin",
+			goos:     "darwin",
+			home:     "/Users/bob",
+			expected: map[string]string{"HOME": filepath.Join(root, "Users", "bob"), "APPDATA": ""},
+		},
+		{
+			name:     "LinuxRelative",
+			goos:     "linux",
+			home:     "home/carol",
+			expected: map[string]string{"HOME": filepath.Join(root, "home", "carol")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := RootedEnvironment(root, tt.goos, tt.home)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if env.OS != tt.goos {
+				t.Errorf("OS = %q, want %q", env.OS, tt.goos)
+			}
+			for key, want := range tt.expected {
+				if got := env.Getenv(key); got != want {
+					t.Errorf("Getenv(%q) = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRootedEnvironment_Invalid(t *testing.T) {
+	if _, err := RootedEnvironment("/mnt", "plan9", "/usr/glenda"); err == nil {
+		t.Error("Expected an error for an unsupported target OS")
+	}
+	if _, err := RootedEnvironment("/mnt", "linux", ""); err == nil {
+		t.Error("Expected an error for a missing home directory")
+	}
+}
+
+func TestEnvironment_WindowsImage(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("LOCALAPPDATA", filepath.Join(root, "should-not-be-used"))
+
+	homeDir := filepath.Join(root, "Users", "alice")
+	chromeProfile := filepath.Join(homeDir, "AppData", "Local", "Google", "Chrome", "User Data", "Default")
+	if err := os.MkdirAll(chromeProfile, 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	firefoxDir := filepath.Join(homeDir, "AppData", "Roaming", "Mozilla", "Firefox")
+	if err := os.MkdirAll(filepath.Join(firefoxDir, "Profiles", "abc.default"), 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	placesPath := filepath.Join(firefoxDir, "Profiles", "abc.default", "places.sqlite")
+	if err := os.WriteFile(placesPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+	cfg := ini.Empty()
+	section, _ := cfg.NewSection("Profile0")
+	section.NewKey("Path", "Profiles/abc.default")
+	section.NewKey("IsRelative", "1")
+	cfg.SaveTo(filepath.Join(firefoxDir, "profiles.ini"))
+
+	env, err := RootedEnvironment(root, "windows", `C:\Users\alice`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	browsers := All()
+	paths, err := browsers["chrome"].(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected chrome error: %v", err)
+	}
+	expected := history.HistoryPathEntry{Profile: "Default", Path: filepath.Join(chromeProfile, "History"), Channel: "stable", User: "alice"}
+	if len(paths) != 1 || paths[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, paths)
+	}
+
+	paths, err = browsers["firefox"].(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected firefox error: %v", err)
+	}
+	if len(paths) != 1 || paths[0].Path != placesPath {
+		t.Errorf("Expected %s, got %v", placesPath, paths)
+	}
+}
+
+func TestEnvironment_FirefoxAbsoluteProfileInImage(t *testing.T) {
+	root := t.TempDir()
+	firefoxDir := filepath.Join(root, "Users", "alice", "AppData", "Roaming", "Mozilla", "Firefox")
+	imageProfile := filepath.Join(root, "Data", "abc.default")
+	if err := os.MkdirAll(firefoxDir, 0755); err != nil {
+		t.Fatalf("Failed to create Firefox dir: %v", err)
+	}
+	if err := os.MkdirAll(imageProfile, 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(imageProfile, "places.sqlite"), nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+
+	// A profile of the examiner's own system at the same absolute path must not be read.
+	hostProfile := t.TempDir()
+	if err := os.WriteFile(filepath.Join(hostProfile, "places.sqlite"), nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+
+	cfg := ini.Empty()
+	section, _ := cfg.NewSection("Profile0")
+	section.NewKey("Path", `D:\Data\abc.default`)
+	section.NewKey("IsRelative", "0")
+	section, _ = cfg.NewSection("Profile1")
+	section.NewKey("Path", hostProfile)
+	section.NewKey("IsRelative", "0")
+	cfg.SaveTo(filepath.Join(firefoxDir, "profiles.ini"))
+
+	env, err := RootedEnvironment(root, "windows", `C:\Users\alice`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paths, err := All()["firefox"].(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 1 || paths[0].Path != filepath.Join(imageProfile, "places.sqlite") {
+		t.Errorf("Expected only the profile inside the image, got %v", paths)
+	}
+}
+
+func TestUserEnvironments(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		dirs     []string
+		expected []string
+	}{
+		{
+			name:     "Linux",
+			goos:     "linux",
+			dirs:     []string{"home/alice", "home/bob", "home/lost+found", "home/.ecryptfs", "root"},
+			expected: []string{"alice", "bob", "root"},
+		},
+		{
+			name:     "Darwin",
+			goos:     "darwin",
+			dirs:     []string{"Users/alice", "Users/Shared", "Users/.localized"},
+			expected: []string{"alice"},
+		},
+		{
+			name:     "Windows",
+			goos:     "windows",
+			dirs:     []string{"Users/alice", "Users/Public", "Users/Default", "Users/Default User", "Users/All Users", "Users/bob"},
+			expected: []string{"alice", "bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range tt.dirs {
+				if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+					t.Fatalf("Failed to create %s: %v", dir, err)
+				}
+			}
+
+			envs, err := UserEnvironments(root, tt.goos)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var users []string
+			for _, env := range envs {
+				users = append(users, env.User)
+				if env.OS != tt.goos {
+					t.Errorf("OS = %q, want %q", env.OS, tt.goos)
+				}
+			}
+			if len(users) != len(tt.expected) {
+				t.Fatalf("Users = %v, want %v", users, tt.expected)
+			}
+			for i := range users {
+				if users[i] != tt.expected[i] {
+					t.Errorf("Users = %v, want %v", users, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestUserEnvironments_ProfilesTaggedWithUser(t *testing.T) {
+	root := t.TempDir()
+	for _, user := range []string{"alice", "bob"} {
+		profileDir := filepath.Join(root, "home", user, ".config", "google-chrome", "Default")
+		if err := os.MkdirAll(profileDir, 0755); err != nil {
+			t.Fatalf("Failed to create profile dir: %v", err)
+		}
+	}
+
+	envs, err := UserEnvironments(root, "linux")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	chrome := All()["chrome"].(EnvironmentBrowser)
+	for _, env := range envs {
+		paths, err := chrome.GetHistoryPathsIn(env)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", env.User, err)
+		}
+		if len(paths) != 1 || paths[0].User != env.User {
+			t.Errorf("Expected one path tagged %s, got %v", env.User, paths)
+		}
+	}
+}
+
+func TestImagePath(t *testing.T) {
+	tests := map[string]string{
+		`C:\Users\alice`: "Users/alice",
+		"C:/Users/alice": "Users/alice",
+		"/home/bob":      "home/bob",
+		"Users/carol":    "Users/carol",
+	}
+	for input, want := range tests {
+		if got := imagePath(input); got != want {
+			t.Errorf("imagePath(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestEnvironment_XDGDefaults(t *testing.T) {
+	env := Environment{OS: "linux", Vars: map[string]string{"HOME": "/home/dave", "XDG_CONFIG_HOME": "/srv/config"}}
+	if got := env.Getenv("XDG_CONFIG_HOME"); got != "/srv/config" {
+		t.Errorf("Expected explicit XDG_CONFIG_HOME to be kept, got %q", got)
+	}
+	if got, want := env.Getenv("XDG_DATA_HOME"), filepath.Join("/home/dave", ".local", "share"); got != want {
+		t.Errorf("Expected XDG_DATA_HOME default %q, got %q", want, got)
+	}
+
+	t.Setenv("HOME", "/home/erin")
+	t.Setenv("XDG_DATA_HOME", "")
+	if got, want := CurrentEnvironment().Getenv("XDG_DATA_HOME"), filepath.Join("/home/erin", ".local", "share"); got != want {
+		t.Errorf("Expected XDG_DATA_HOME default %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,140 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// epiphanyHistoryQuery is the SQL query for retrieving GNOME Web history entries. Visit times are
+// stored in seconds since the Unix epoch and visit types follow EphyHistoryPageVisitType.
+const epiphanyHistoryQuery = `
+	SELECT
+		urls.url,
+		urls.title,
+		urls.visit_count,
+		urls.typed_count,
+		CASE visits.visit_type
+			WHEN 1 THEN 'LINK'
+			WHEN 2 THEN 'TYPED'
+			WHEN 3 THEN 'MANUAL_BOOKMARK'
+			WHEN 4 THEN 'BOOKMARK'
+			WHEN 5 THEN 'HOMEPAGE'
+			ELSE 'NONE'
+		END AS visit_type_desc,
+		visits.visit_time
+	FROM visits
+	JOIN urls ON urls.id = visits.url
+	WHERE visits.visit_time >= ? AND visits.visit_time <= ?
+	ORDER BY visits.visit_time DESC`
+
+// epiphanyVisitTypes maps the visit types named by epiphanyHistoryQuery to normalized visit types.
+var epiphanyVisitTypes = map[string]history.VisitType{
+	"LINK":            history.VisitLink,
+	"TYPED":           history.VisitTyped,
+	"MANUAL_BOOKMARK": history.VisitBookmark,
+	"BOOKMARK":        history.VisitBookmark,
+	"HOMEPAGE":        history.VisitLink,
+}
+
+// EpiphanyBrowser implements the Browser interface for GNOME Web (Epiphany).
+type EpiphanyBrowser struct {
+	Descriptor Descriptor
+}
+
+// NewEpiphanyBrowser creates a new instance of EpiphanyBrowser.
+func NewEpiphanyBrowser() Browser {
+	return &EpiphanyBrowser{Descriptor: epiphanyDescriptor}
+}
+
+// GetHistoryPaths retrieves collection of paths to GNOME Web's history database files.
+func (eb *EpiphanyBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return eb.GetHistoryPathsIn(CurrentEnvironment())
+}
+
+// GetHistoryPathsIn retrieves the history database paths from every GNOME Web profile directory in env.
+func (eb *EpiphanyBrowser) GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error) {
+	return eb.Descriptor.historyPaths(env, eb.getPaths)
+}
+
+// GetHistoryPathsFrom retrieves the history database path for a GNOME Web profile directory.
+func (eb *EpiphanyBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return eb.getPaths(dir)
+}
+
+// getPaths returns the profile's ephy-history.db. GNOME Web keeps a single profile per data directory.
+func (eb *EpiphanyBrowser) getPaths(dir string) ([]history.HistoryPathEntry, error) {
+	historyPath := filepath.Join(dir, EpiphanyHistoryFile)
+	if _, err := os.Stat(historyPath); err != nil {
+		return nil, err
+	}
+	return []history.HistoryPathEntry{{Profile: "Default", ProfileName: "Default", Path: historyPath}}, nil
+}
+
+// ExtractHistory extracts GNOME Web history entries within the given time range.
+func (eb *EpiphanyBrowser) ExtractHistory(ctx context.Context, historyDBPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to open GNOME Web history database at %s: %v", historyDBPath, err))
+			return
+		}
+		defer db.Close()
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Querying GNOME Web history from %s, start: %v, end: %v\n", historyDBPath, startTime, endTime)
+		}
+		rows, err := db.QueryContext(ctx, epiphanyHistoryQuery, startTime.Unix(), endTime.Unix())
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to query GNOME Web history from %s: %v", historyDBPath, err))
+			return
+		}
+		defer rows.Close()
+
+		count := 0
+		for rows.Next() {
+			var pageURL, pageVisitType string
+			var pageTitle sql.NullString
+			var pageVisitCount, pageTyped int
+			var visitTimestamp int64
+			if err := rows.Scan(
+				&pageURL,
+				&pageTitle,
+				&pageVisitCount,
+				&pageTyped,
+				&pageVisitType,
+				&visitTimestamp); err != nil {
+				yield(history.HistoryEntry{}, fmt.Errorf("failed to scan GNOME Web history row from %s: %v", historyDBPath, err))
+				return
+			}
+			count++
+			if !yield(history.HistoryEntry{
+				URL:             pageURL,
+				Title:           pageTitle.String,
+				VisitCount:      pageVisitCount,
+				Typed:           pageTyped,
+				VisitType:       normalizeVisitType(epiphanyVisitTypes, pageVisitType),
+				NativeVisitType: pageVisitType,
+				Timestamp:       time.Unix(visitTimestamp, 0),
+				Profile:         profile,
+			}, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("error iterating GNOME Web history rows from %s: %v", historyDBPath, err))
+			return
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Retrieved %d entries from GNOME Web\n", count)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestEpiphanyBrowser_GetHistoryPathsIn(t *testing.T) {
+	home := t.TempDir()
+	nativeDir := filepath.Join(home, ".local", "share", "epiphany")
+	flatpakDir := filepath.Join(home, ".var", "app", "org.gnome.Epiphany", "data", "epiphany")
+	for _, dir := range []string{nativeDir, flatpakDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create data dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ephy-history.db"), nil, 0644); err != nil {
+			t.Fatalf("Failed to create ephy-history.db: %v", err)
+		}
+	}
+
+	env := Environment{OS: "linux", Vars: map[string]string{"HOME": home}}
+	paths, err := NewEpiphanyBrowser().(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []history.HistoryPathEntry{
+		{Profile: "Default", ProfileName: "Default", Path: filepath.Join(nativeDir, "ephy-history.db")},
+		{Profile: "Default", ProfileName: "Default", Path: filepath.Join(flatpakDir, "ephy-history.db"), Variant: VariantFlatpak},
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], paths[i])
+		}
+	}
+}
+
+func TestEpiphanyBrowser_ExtractHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "ephy-history.db")
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	visitTime := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+	_, err = db.Exec(`
+        CREATE TABLE urls (
+            id INTEGER PRIMARY KEY,
+            host INTEGER NOT NULL,
+            url LONGVARCAR,
+            title LONGVARCAR,
+            visit_count INTEGER DEFAULT 0 NOT NULL,
+            typed_count INTEGER DEFAULT 0 NOT NULL,
+            last_visit_time INTEGER
+        );
+        CREATE TABLE visits (
+            id INTEGER PRIMARY KEY,
+            url INTEGER NOT NULL,
+            visit_time INTEGER NOT NULL,
+            visit_type INTEGER NOT NULL,
+            referring_visit INTEGER
+        );
+        INSERT INTO urls VALUES (1, 1, 'https://test.com', 'Test', 2, 1, ?);
+        INSERT INTO visits VALUES (1, 1, ?, 2, NULL);
+        INSERT INTO visits VALUES (2, 1, ?, 1, 1);
+    `, visitTime.Unix(), visitTime.Add(-time.Minute).Unix(), visitTime.Unix())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	eb := &EpiphanyBrowser{}
+	entries, err := history.Collect(eb.ExtractHistory(context.Background(), dbPath, "Default", time.Now().Add(-2*time.Hour), time.Now(), false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].VisitType != history.VisitLink || entries[0].NativeVisitType != "LINK" ||
+		entries[1].VisitType != history.VisitTyped || entries[1].NativeVisitType != "TYPED" {
+		t.Errorf("Unexpected visit types %q (%q), %q (%q)",
+			entries[0].VisitType, entries[0].NativeVisitType, entries[1].VisitType, entries[1].NativeVisitType)
+	}
+	if !entries[0].Timestamp.Equal(visitTime) || entries[0].Typed != 1 || entries[0].VisitCount != 2 || entries[0].Title != "Test" {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+}
@@ -0,0 +1,92 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFalkonBrowser_GetHistoryPathsIn(t *testing.T) {
+	home := t.TempDir()
+	configHome := filepath.Join(home, "config")
+	profilesDir := filepath.Join(configHome, "falkon", "profiles")
+	for _, profile := range []string{"default", "work"} {
+		if err := os.MkdirAll(filepath.Join(profilesDir, profile), 0755); err != nil {
+			t.Fatalf("Failed to create profile dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(profilesDir, profile, "browsedata.db"), nil, 0644); err != nil {
+			t.Fatalf("Failed to create browsedata.db: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(profilesDir, "profiles.ini"), []byte("[Profiles]\nstartProfile=default\n"), 0644); err != nil {
+		t.Fatalf("Failed to create profiles.ini: %v", err)
+	}
+
+	env := Environment{OS: "linux", Vars: map[string]string{"HOME": home, "XDG_CONFIG_HOME": configHome}}
+	paths, err := NewFalkonBrowser().(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []history.HistoryPathEntry{
+		{Profile: "default", ProfileName: "default", Path: filepath.Join(profilesDir, "default", "browsedata.db")},
+		{Profile: "work", ProfileName: "work", Path: filepath.Join(profilesDir, "work", "browsedata.db")},
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], paths[i])
+		}
+	}
+
+	single, err := (&FalkonBrowser{}).GetHistoryPathsFrom(filepath.Join(profilesDir, "work"))
+	if err != nil || len(single) != 1 || single[0] != expected[1] {
+		t.Errorf("Expected [%v] for a single profile dir, got %v (%v)", expected[1], single, err)
+	}
+}
+
+func TestFalkonBrowser_ExtractHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "browsedata.db")
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	visitTime := time.Now().Add(-1 * time.Hour).Truncate(time.Millisecond)
+	_, err = db.Exec(`
+        CREATE TABLE history (
+            id INTEGER PRIMARY KEY,
+            url TEXT NOT NULL,
+            title TEXT,
+            date INTEGER,
+            count INTEGER
+        );
+        INSERT INTO history VALUES (1, 'https://test.com', 'Test', ?, 4);
+        INSERT INTO history VALUES (2, 'https://old.com', 'Old', ?, 1);
+    `, visitTime.UnixMilli(), time.Now().Add(-48*time.Hour).UnixMilli())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	fb := &FalkonBrowser{}
+	entries, err := history.Collect(fb.ExtractHistory(context.Background(), dbPath, "default", time.Now().Add(-2*time.Hour), time.Now(), false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].URL != "https://test.com" || entries[0].VisitCount != 4 || !entries[0].Timestamp.Equal(visitTime) {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+}
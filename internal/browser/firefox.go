@@ -1,11 +1,12 @@
 package browser
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
@@ -32,45 +33,87 @@ const firefoxHistoryQuery = `
 			WHEN 9 THEN 'TRANSITION_RELOAD'
         ELSE 'UNKNOWN (' || moz_historyvisits.visit_type || ')'
 		END AS visit_day_desc, 
-		moz_historyvisits.visit_date 
+		moz_historyvisits.visit_date,
+		CASE WHEN moz_historyvisits.visit_type IN (4, 8) THEN 0 ELSE IFNULL((
+			SELECT MIN(next_visit.visit_date)
+			FROM moz_historyvisits AS next_visit
+			WHERE next_visit.visit_date > moz_historyvisits.visit_date
+			AND next_visit.visit_type NOT IN (4, 8)), 0)
+		END AS next_visit_date,
+		moz_historyvisits.id,
+		IFNULL(moz_historyvisits.from_visit, 0),
+		IFNULL(referrer_place.url, '')
 		FROM moz_places
     	JOIN moz_historyvisits ON moz_historyvisits.place_id = moz_places.id
+		LEFT JOIN moz_historyvisits AS referrer ON referrer.id = moz_historyvisits.from_visit
+		LEFT JOIN moz_places AS referrer_place ON referrer_place.id = referrer.place_id
 		WHERE moz_historyvisits.visit_date >= ? AND moz_historyvisits.visit_date <= ?
     ORDER BY moz_historyvisits.visit_date DESC`
 
-type FirefoxBrowser struct{}
+// firefoxVisitTypes maps the visit types named by firefoxHistoryQuery to normalized visit types.
+var firefoxVisitTypes = map[string]history.VisitType{
+	"TRANSITION_LINK":               history.VisitLink,
+	"TRANSITION_TYPED":              history.VisitTyped,
+	"TRANSITION_BOOKMARK":           history.VisitBookmark,
+	"TRANSITION_EMBED":              history.VisitEmbed,
+	"TRANSITION_REDIRECT_PERMANENT": history.VisitRedirectPermanent,
+	"TRANSITION_REDIRECT_TEMPORARY": history.VisitRedirectTemporary,
+	"TRANSITION_DOWNLOAD":           history.VisitDownload,
+	"TRANSITION_FRAMED_LINK":        history.VisitEmbed,
+	"TRANSITION_RELOAD":             history.VisitReload,
+}
+
+// geckoMaxDwell is the longest gap between consecutive visits still counted as time spent on
+// the earlier page; longer gaps are treated as the user having been away.
+const geckoMaxDwell = 30 * time.Minute
+
+// FirefoxBrowser implements the Browser interface for Mozilla Firefox and any Gecko-based
+// fork that uses the same profiles.ini layout and places.sqlite schema.
+type FirefoxBrowser struct {
+	Descriptor Descriptor
+}
 
+// NewFirefoxBrowser creates a new instance of FirefoxBrowser for Mozilla Firefox.
 func NewFirefoxBrowser() Browser {
-	return &FirefoxBrowser{}
+	return NewGeckoBrowser(firefoxDescriptor)
+}
+
+// NewGeckoBrowser creates a new FirefoxBrowser for the Gecko-based browser described by d.
+func NewGeckoBrowser(d Descriptor) Browser {
+	return &FirefoxBrowser{Descriptor: d}
 }
 
 // GetHistoryPath retrieves collection of paths to Firefox's history database file.
 func (fb *FirefoxBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
-	baseDir, err := fb.getFirefoxProfileBaseDir()
-	if err != nil {
-		return nil, err
-	}
-	return fb.getPaths(baseDir)
+	return fb.GetHistoryPathsIn(CurrentEnvironment())
 }
 
-func (fb *FirefoxBrowser) getFirefoxProfileBaseDir() (string, error) {
-	switch runtime.GOOS {
-	case "windows":
-		return os.Getenv("APPDATA") + "\\Mozilla\\Firefox\\", nil
-	case "darwin":
-		return os.Getenv("HOME") + "/Library/Application Support/Firefox/", nil
-	case "linux":
-		return os.Getenv("HOME") + "/.mozilla/firefox/", nil
-	default:
-		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-	}
+// GetHistoryPathsIn retrieves the history database paths from every profiles.ini location in env.
+func (fb *FirefoxBrowser) GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error) {
+	return fb.Descriptor.historyPaths(env, func(dir string) ([]history.HistoryPathEntry, error) {
+		return fb.getPaths(env, dir)
+	})
+}
+
+// GetHistoryPathsFrom retrieves the history database paths for a directory containing profiles.ini or a single profile directory.
+func (fb *FirefoxBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return fb.getPaths(CurrentEnvironment(), dir)
 }
 
-// GetBrowserProfilePaths gets a collection of browser profile history paths.
-func (fb *FirefoxBrowser) getPaths(dir string) ([]history.HistoryPathEntry, error) {
+// getPaths returns the profiles listed in dir's profiles.ini. Absolute profile paths are
+// resolved inside env, so those of a mounted image are read from the image, not the host.
+func (fb *FirefoxBrowser) getPaths(env Environment, dir string) ([]history.HistoryPathEntry, error) {
 	profileIniFile := filepath.Join(dir, "profiles.ini")
 	cfg, err := ini.Load(profileIniFile)
 	if err != nil {
+		// A profile directory copied on its own has places.sqlite but no profiles.ini.
+		placesPath := filepath.Join(dir, "places.sqlite")
+		if _, statErr := os.Stat(placesPath); statErr == nil {
+			return []history.HistoryPathEntry{{
+				Profile: filepath.Base(dir),
+				Path:    placesPath,
+			}}, nil
+		}
 		return nil, fmt.Errorf("failed to load profiles.ini: %w", err)
 	}
 
@@ -90,8 +133,11 @@ func (fb *FirefoxBrowser) getPaths(dir string) ([]history.HistoryPathEntry, erro
 		}
 
 		if isRelative {
-			path = filepath.Join(dir, path, "places.sqlite")
+			path = filepath.Join(dir, path)
+		} else {
+			path = env.imageFile(path)
 		}
+		path = filepath.Join(path, "places.sqlite")
 
 		_, err := os.Stat(path)
 		if os.IsNotExist(err) {
@@ -107,61 +153,92 @@ func (fb *FirefoxBrowser) getPaths(dir string) ([]history.HistoryPathEntry, erro
 }
 
 // ExtractHistory gets records from the defined history db and date range.
-func (fb *FirefoxBrowser) ExtractHistory(historyDBPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.HistoryEntry, error) {
-	db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Firefox history database at %s: %v", historyDBPath, err)
-	}
-	defer db.Close()
+func (fb *FirefoxBrowser) ExtractHistory(ctx context.Context, historyDBPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to open Firefox history database at %s: %v", historyDBPath, err))
+			return
+		}
+		defer db.Close()
 
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Debug: Querying Firefox history from %s, start: %v, end: %v\n", historyDBPath, startTime, endTime)
-		fmt.Fprintf(os.Stderr, "Debug: Query params: start=%d, end=%d\n", startTime.UnixMicro(), endTime.UnixMicro())
-	}
-	rows, err := db.Query(firefoxHistoryQuery, startTime.UnixMicro(), endTime.UnixMicro())
-	if err != nil {
-		return nil, fmt.Errorf("failed to query Firefox history from %s: %v", historyDBPath, err)
-	}
-	defer rows.Close()
-
-	var entries []history.HistoryEntry
-	for rows.Next() {
-		var pageURL, pageVisitType string
-		var pageVisitCount, pageTyped int
-		var pageTitle sql.NullString
-		var visitTimestamp int64
-		if err := rows.Scan(
-			&pageURL,
-			&pageTitle,
-			&pageVisitCount,
-			&pageTyped,
-			&pageVisitType,
-			&visitTimestamp); err != nil {
-			return nil, fmt.Errorf("failed to scan Firefox history row from %s: %v", historyDBPath, err)
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Querying Firefox history from %s, start: %v, end: %v\n", historyDBPath, startTime, endTime)
+			fmt.Fprintf(os.Stderr, "Debug: Query params: start=%d, end=%d\n", startTime.UnixMicro(), endTime.UnixMicro())
 		}
-		title := ""
-		if pageTitle.Valid {
-			title = pageTitle.String
+		rows, err := db.QueryContext(ctx, firefoxHistoryQuery, startTime.UnixMicro(), endTime.UnixMicro())
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to query Firefox history from %s: %v", historyDBPath, err))
+			return
+		}
+		defer rows.Close()
+
+		count := 0
+		for rows.Next() {
+			var pageURL, pageVisitType, referrerURL string
+			var pageVisitCount, pageTyped int
+			var pageTitle sql.NullString
+			var visitTimestamp, nextVisitTimestamp, visitID, referrerVisitID int64
+			if err := rows.Scan(
+				&pageURL,
+				&pageTitle,
+				&pageVisitCount,
+				&pageTyped,
+				&pageVisitType,
+				&visitTimestamp,
+				&nextVisitTimestamp,
+				&visitID,
+				&referrerVisitID,
+				&referrerURL); err != nil {
+				yield(history.HistoryEntry{}, fmt.Errorf("failed to scan Firefox history row from %s: %v", historyDBPath, err))
+				return
+			}
+			title := ""
+			if pageTitle.Valid {
+				title = pageTitle.String
+			}
+			count++
+			if !yield(history.HistoryEntry{
+				URL:             pageURL,
+				Title:           title,
+				VisitCount:      pageVisitCount,
+				Typed:           pageTyped,
+				VisitType:       normalizeVisitType(firefoxVisitTypes, pageVisitType),
+				NativeVisitType: pageVisitType,
+				Timestamp:       time.UnixMicro(visitTimestamp),
+				Duration:        geckoVisitDuration(visitTimestamp, nextVisitTimestamp),
+				VisitID:         visitID,
+				ReferrerVisitID: referrerVisitID,
+				ReferrerURL:     referrerURL,
+				Profile:         profile,
+			}, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("error iterating Firefox history rows from %s: %v", historyDBPath, err))
+			return
 		}
-		entries = append(entries, history.HistoryEntry{
-			URL:        pageURL,
-			Title:      title,
-			VisitCount: pageVisitCount,
-			Typed:      pageTyped,
-			VisitType:  pageVisitType,
-			Timestamp:  time.UnixMicro(visitTimestamp),
-			Profile:    profile,
-		})
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating Firefox history rows from %s: %v", historyDBPath, err)
-	}
 
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Debug: Retrieved %d entries from Firefox\n", len(entries))
-		if len(entries) == 0 {
-			fmt.Fprintf(os.Stderr, "Debug: Warning: No entries found. Database may be empty, history not flushed, or time range incorrect.\n")
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Retrieved %d entries from Firefox\n", count)
+			if count == 0 {
+				fmt.Fprintf(os.Stderr, "Debug: Warning: No entries found. Database may be empty, history not flushed, or time range incorrect.\n")
+			}
 		}
 	}
-	return entries, nil
+}
+
+// geckoVisitDuration estimates the time spent on a visit as the gap until the profile's next
+// top-level visit. Firefox does not record dwell time, so the estimate is zero for the latest
+// visit, for embedded frame visits and for gaps longer than geckoMaxDwell.
+func geckoVisitDuration(visitDate, nextVisitDate int64) time.Duration {
+	if nextVisitDate == 0 {
+		return 0
+	}
+	gap := time.Duration(nextVisitDate-visitDate) * time.Microsecond
+	if gap > geckoMaxDwell {
+		return 0
+	}
+	return gap
 }
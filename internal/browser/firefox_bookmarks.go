@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// firefoxBookmarksQuery walks moz_bookmarks from the root folders down, building each folder's
+// path, and returns every bookmark (type 1) with its URL from moz_places. The tags root is
+// skipped because its children are tag assignments rather than bookmarks.
+const firefoxBookmarksQuery = `
+	WITH RECURSIVE folders(id, path) AS (
+		SELECT
+			moz_bookmarks.id,
+			CASE moz_bookmarks.guid
+				WHEN 'menu________' THEN 'Bookmarks Menu'
+				WHEN 'toolbar_____' THEN 'Bookmarks Toolbar'
+				WHEN 'unfiled_____' THEN 'Other Bookmarks'
+				WHEN 'mobile______' THEN 'Mobile Bookmarks'
+				ELSE IFNULL(moz_bookmarks.title, '')
+			END
+		FROM moz_bookmarks
+		WHERE moz_bookmarks.parent = (SELECT id FROM moz_bookmarks WHERE guid = 'root________')
+			AND moz_bookmarks.guid <> 'tags________'
+		UNION ALL
+		SELECT moz_bookmarks.id, folders.path || '/' || IFNULL(moz_bookmarks.title, '')
+		FROM moz_bookmarks
+		JOIN folders ON moz_bookmarks.parent = folders.id
+		WHERE moz_bookmarks.type = 2
+	)
+	SELECT
+		moz_places.url,
+		IFNULL(moz_bookmarks.title, ''),
+		folders.path,
+		IFNULL(moz_bookmarks.dateAdded, 0),
+		IFNULL(moz_bookmarks.guid, '')
+	FROM moz_bookmarks
+	JOIN folders ON moz_bookmarks.parent = folders.id
+	JOIN moz_places ON moz_places.id = moz_bookmarks.fk
+	WHERE moz_bookmarks.type = 1
+	ORDER BY folders.path, moz_bookmarks.position`
+
+// BookmarksFile returns the profile's places.sqlite, which holds bookmarks alongside history.
+func (fb *FirefoxBrowser) BookmarksFile(historyPath string) string {
+	return historyPath
+}
+
+// ExtractBookmarks retrieves every bookmark from a places.sqlite database.
+func (fb *FirefoxBrowser) ExtractBookmarks(ctx context.Context, bookmarksPath, profile string, verbose bool) ([]history.BookmarkEntry, error) {
+	db, err := sql.Open("sqlite3", "file:"+bookmarksPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Firefox bookmarks database at %s: %v", bookmarksPath, err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, firefoxBookmarksQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Firefox bookmarks from %s: %v", bookmarksPath, err)
+	}
+	defer rows.Close()
+
+	var entries []history.BookmarkEntry
+	for rows.Next() {
+		var bookmarkURL, bookmarkTitle, folder, guid string
+		var dateAdded int64
+		if err := rows.Scan(&bookmarkURL, &bookmarkTitle, &folder, &dateAdded, &guid); err != nil {
+			return nil, fmt.Errorf("failed to scan Firefox bookmark row from %s: %v", bookmarksPath, err)
+		}
+		entry := history.BookmarkEntry{
+			URL:     bookmarkURL,
+			Title:   bookmarkTitle,
+			Folder:  folder,
+			GUID:    guid,
+			Profile: profile,
+		}
+		if dateAdded != 0 {
+			entry.DateAdded = time.UnixMicro(dateAdded)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating Firefox bookmark rows from %s: %v", bookmarksPath, err)
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Retrieved %d bookmarks from %s\n", len(entries), bookmarksPath)
+	}
+	return entries, nil
+}
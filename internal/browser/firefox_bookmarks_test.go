@@ -0,0 +1,73 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFirefoxBrowser_ExtractBookmarks(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "places.sqlite")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	added := time.Now().Add(-24 * time.Hour).Truncate(time.Microsecond)
+	_, err = db.Exec(`
+        CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, typed INTEGER);
+        CREATE TABLE moz_bookmarks (
+            id INTEGER PRIMARY KEY,
+            type INTEGER,
+            fk INTEGER DEFAULT NULL,
+            parent INTEGER,
+            position INTEGER,
+            title LONGVARCHAR,
+            dateAdded INTEGER,
+            lastModified INTEGER,
+            guid TEXT
+        );
+        INSERT INTO moz_places VALUES (1, 'https://www.mozilla.org/', 'Mozilla', 1, 0);
+        INSERT INTO moz_places VALUES (2, 'https://go.dev/', 'Go', 1, 0);
+        INSERT INTO moz_bookmarks VALUES (1, 2, NULL, 0, 0, '', 0, 0, 'root________');
+        INSERT INTO moz_bookmarks VALUES (2, 2, NULL, 1, 0, 'menu', 0, 0, 'menu________');
+        INSERT INTO moz_bookmarks VALUES (3, 2, NULL, 1, 1, 'toolbar', 0, 0, 'toolbar_____');
+        INSERT INTO moz_bookmarks VALUES (4, 2, NULL, 1, 2, 'tags', 0, 0, 'tags________');
+        INSERT INTO moz_bookmarks VALUES (5, 2, NULL, 3, 0, 'Dev', ?, 0, 'devfolder___');
+        INSERT INTO moz_bookmarks VALUES (6, 1, 2, 5, 0, 'Go', ?, 0, 'gobookmark__');
+        INSERT INTO moz_bookmarks VALUES (7, 1, 1, 2, 0, 'Mozilla', ?, 0, 'mozbookmark_');
+        INSERT INTO moz_bookmarks VALUES (8, 2, NULL, 4, 0, 'golang', 0, 0, 'tagfolder___');
+        INSERT INTO moz_bookmarks VALUES (9, 1, 2, 8, 0, NULL, 0, 0, 'tagentry____');
+        INSERT INTO moz_bookmarks VALUES (10, 3, NULL, 2, 1, NULL, 0, 0, 'separator___');
+    `, added.UnixMicro(), added.UnixMicro(), added.UnixMicro())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	fb := &FirefoxBrowser{}
+	if got := fb.BookmarksFile(dbPath); got != dbPath {
+		t.Errorf("Expected bookmarks file %s, got %s", dbPath, got)
+	}
+	entries, err := fb.ExtractBookmarks(context.Background(), dbPath, "default", false)
+	if err != nil {
+		t.Fatalf("ExtractBookmarks failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 bookmarks, got %+v", entries)
+	}
+	folders := map[string]string{}
+	for _, entry := range entries {
+		folders[entry.URL] = entry.Folder
+		if !entry.DateAdded.Equal(added) || entry.Profile != "default" {
+			t.Errorf("Unexpected bookmark %+v", entry)
+		}
+	}
+	if folders["https://go.dev/"] != "Bookmarks Toolbar/Dev" || folders["https://www.mozilla.org/"] != "Bookmarks Menu" {
+		t.Errorf("Unexpected folders %v", folders)
+	}
+}
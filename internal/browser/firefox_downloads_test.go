@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFirefoxBrowser_ExtractDownloads(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "places.sqlite")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Now().Add(-1 * time.Hour).Truncate(time.Microsecond)
+	finished := started.Add(30 * time.Second).Truncate(time.Millisecond)
+	_, err = db.Exec(`
+        CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, typed INTEGER);
+        CREATE TABLE moz_anno_attributes (id INTEGER PRIMARY KEY, name VARCHAR(32) UNIQUE NOT NULL);
+        CREATE TABLE moz_annos (
+            id INTEGER PRIMARY KEY,
+            place_id INTEGER NOT NULL,
+            anno_attribute_id INTEGER,
+            content LONGVARCHAR,
+            flags INTEGER DEFAULT 0,
+            expiration INTEGER DEFAULT 0,
+            type INTEGER DEFAULT 0,
+            dateAdded INTEGER DEFAULT 0,
+            lastModified INTEGER DEFAULT 0
+        );
+        INSERT INTO moz_places VALUES (1, 'https://example.com/report.pdf', 'report.pdf', 1, 0);
+        INSERT INTO moz_places VALUES (2, 'https://example.com/tool.exe', 'tool.exe', 1, 0);
+        INSERT INTO moz_anno_attributes VALUES (1, 'downloads/destinationFileURI');
+        INSERT INTO moz_anno_attributes VALUES (2, 'downloads/metaData');
+        INSERT INTO moz_annos VALUES (1, 1, 1, 'file:///home/bob/Downloads/report%20final.pdf', 0, 4, 3, ?, ?);
+        INSERT INTO moz_annos VALUES (2, 1, 2, ?, 0, 4, 3, ?, ?);
+        INSERT INTO moz_annos VALUES (3, 2, 1, 'file:///C:/Users/bob/Downloads/tool.exe', 0, 4, 3, ?, ?);
+    `, started.UnixMicro(), started.UnixMicro(),
+		fmt.Sprintf(`{"state":1,"deleted":false,"endTime":%d,"fileSize":2048}`, finished.UnixMilli()), started.UnixMicro(), started.UnixMicro(),
+		started.Add(-time.Minute).UnixMicro(), started.Add(-time.Minute).UnixMicro())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	fb := &FirefoxBrowser{}
+	entries, err := fb.ExtractDownloads(context.Background(), dbPath, "default", time.Now().Add(-2*time.Hour), time.Now(), false)
+	if err != nil {
+		t.Fatalf("ExtractDownloads failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 downloads, got %+v", entries)
+	}
+
+	report := entries[0]
+	if report.TargetPath != "/home/bob/Downloads/report final.pdf" || report.State != "COMPLETE" ||
+		report.TotalBytes != 2048 || report.ReceivedBytes != 2048 || !report.EndTime.Equal(finished) ||
+		!report.StartTime.Equal(started) || len(report.URLChain) != 1 || report.URLChain[0] != "https://example.com/report.pdf" {
+		t.Errorf("Unexpected download %+v", report)
+	}
+
+	tool := entries[1]
+	if tool.TargetPath != `C:\Users\bob\Downloads\tool.exe` || tool.State != "" || !tool.EndTime.IsZero() {
+		t.Errorf("Unexpected download without metadata %+v", tool)
+	}
+}
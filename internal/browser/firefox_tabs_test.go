@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const firefoxSessionJSON = `{
+  "version": ["sessionrestore", 1],
+  "windows": [{
+    "tabs": [
+      {
+        "entries": [
+          {"url": "https://www.mozilla.org/", "title": "Mozilla"},
+          {"url": "https://www.mozilla.org/firefox/", "title": "Firefox"}
+        ],
+        "index": 1,
+        "lastAccessed": 1743940800000,
+        "pinned": true
+      },
+      {"entries": [], "userTypedValue": "https://loading.example.com/", "lastAccessed": 1743940860000}
+    ],
+    "_closedTabs": [{
+      "state": {"entries": [{"url": "https://closed.example.com/", "title": "Closed"}], "index": 1},
+      "title": "Closed",
+      "closedAt": 1743940900000
+    }]
+  }],
+  "_closedWindows": [{
+    "tabs": [{"entries": [{"url": "https://old.example.com/", "title": "Old"}], "index": 1}],
+    "closedAt": 1743930000000
+  }],
+  "session": {"lastUpdate": 1743941000000}
+}`
+
+func TestFirefoxBrowser_ExtractSession(t *testing.T) {
+	profileDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(profileDir, "sessionstore-backups"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	recoveryPath := filepath.Join(profileDir, "sessionstore-backups", "recovery.jsonlz4")
+	writeMozLz4File(t, recoveryPath, []byte(firefoxSessionJSON))
+
+	fb := &FirefoxBrowser{}
+	files := fb.SessionFiles(filepath.Join(profileDir, "places.sqlite"))
+	if len(files) != 1 || files[0] != recoveryPath {
+		t.Fatalf("Expected [%s], got %v", recoveryPath, files)
+	}
+
+	session, err := fb.ExtractSession(context.Background(), recoveryPath, recoveryPath, "default-release", false)
+	if err != nil {
+		t.Fatalf("ExtractSession failed: %v", err)
+	}
+	if !session.LastUpdate.Equal(time.UnixMilli(1743941000000)) || session.Profile != "default-release" {
+		t.Errorf("Unexpected session %+v", session)
+	}
+	if len(session.Windows) != 1 || len(session.Windows[0].Tabs) != 2 || len(session.Windows[0].ClosedTabs) != 1 {
+		t.Fatalf("Unexpected windows %+v", session.Windows)
+	}
+
+	pinned := session.Windows[0].Tabs[0]
+	if pinned.URL != "https://www.mozilla.org/" || pinned.Title != "Mozilla" || !pinned.Pinned ||
+		pinned.CurrentIndex != 0 || len(pinned.Navigations) != 2 || !pinned.LastAccessed.Equal(time.UnixMilli(1743940800000)) {
+		t.Errorf("Unexpected pinned tab %+v", pinned)
+	}
+	if loading := session.Windows[0].Tabs[1]; loading.URL != "https://loading.example.com/" || len(loading.Navigations) != 0 {
+		t.Errorf("Unexpected loading tab %+v", loading)
+	}
+	if closed := session.Windows[0].ClosedTabs[0]; closed.URL != "https://closed.example.com/" || !closed.ClosedAt.Equal(time.UnixMilli(1743940900000)) {
+		t.Errorf("Unexpected closed tab %+v", closed)
+	}
+	if len(session.ClosedWindows) != 1 || session.ClosedWindows[0].Tabs[0].Title != "Old" ||
+		!session.ClosedWindows[0].ClosedAt.Equal(time.UnixMilli(1743930000000)) {
+		t.Errorf("Unexpected closed windows %+v", session.ClosedWindows)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fb.ExtractSession(ctx, recoveryPath, recoveryPath, "default-release", false); err != context.Canceled {
+		t.Errorf("Expected a cancelled context to stop reading the session, got %v", err)
+	}
+}
+
+func TestFirefoxBrowser_ExtractSessionInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessionstore.jsonlz4")
+	if err := os.WriteFile(path, []byte(`{"windows": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&FirefoxBrowser{}).ExtractSession(context.Background(), path, path, "default", false); err == nil {
+		t.Error("Expected an error for an uncompressed session file")
+	}
+}
@@ -1,9 +1,11 @@
 package browser
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -46,7 +48,7 @@ func TestFirefoxBrowser_GetHistoryPath(t *testing.T) {
 	}
 	file.Close() // Explicitly close the file to avoid lock
 
-	paths, err := fb.getPaths(tempDir)
+	paths, err := fb.getPaths(CurrentEnvironment(), tempDir)
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -54,7 +56,113 @@ func TestFirefoxBrowser_GetHistoryPath(t *testing.T) {
 		t.Errorf("Expected 1 path, got %d", len(paths))
 	}
 	if paths[0] != placesPaths {
-		t.Errorf("Expected path %s, got %s", placesPath, paths[0])
+		t.Errorf("Expected path %s, got %s", placesPath, paths[0].Path)
+	}
+}
+
+func TestFirefoxBrowser_GetHistoryPath_AbsoluteProfile(t *testing.T) {
+	fb := &FirefoxBrowser{}
+	tempDir := t.TempDir()
+	profileDir := filepath.Join(t.TempDir(), "elsewhere")
+	if err := os.Mkdir(profileDir, 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	placesPath := filepath.Join(profileDir, "places.sqlite")
+	if err := os.WriteFile(placesPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+
+	cfg := ini.Empty()
+	section, _ := cfg.NewSection("Profile0")
+	section.NewKey("Name", "absolute")
+	section.NewKey("Path", profileDir)
+	section.NewKey("IsRelative", "0")
+	cfg.SaveTo(filepath.Join(tempDir, "profiles.ini"))
+
+	paths, err := fb.getPaths(CurrentEnvironment(), tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := history.HistoryPathEntry{Profile: "Profile0", ProfileName: "absolute", Path: placesPath}
+	if len(paths) != 1 || paths[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, paths)
+	}
+}
+
+func TestFirefoxBrowser_GetHistoryPathsFrom_ProfileDir(t *testing.T) {
+	profileDir := filepath.Join(t.TempDir(), "copied.default-release")
+	if err := os.Mkdir(profileDir, 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	placesPath := filepath.Join(profileDir, "places.sqlite")
+	if err := os.WriteFile(placesPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create places.sqlite: %v", err)
+	}
+
+	paths, err := NewFirefoxBrowser().(DirectoryBrowser).GetHistoryPathsFrom(profileDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := history.HistoryPathEntry{Profile: "copied.default-release", Path: placesPath}
+	if len(paths) != 1 || paths[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, paths)
+	}
+
+	if _, err := NewFirefoxBrowser().(DirectoryBrowser).GetHistoryPathsFrom(t.TempDir()); err == nil {
+		t.Error("Expected an error for a directory without profiles.ini or places.sqlite")
+	}
+}
+
+func TestGeckoForks_GetHistoryPaths(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("Fork layouts are exercised on linux only, running on %s", runtime.GOOS)
+	}
+	tests := []struct {
+		browser string
+		baseDir []string
+	}{
+		{"firefox", []string{".mozilla", "firefox"}},
+		{"librewolf", []string{".librewolf"}},
+		{"waterfox", []string{".waterfox"}},
+		{"floorp", []string{".floorp"}},
+		{"zen", []string{".zen"}},
+		{"tor", []string{"tor-browser", "Browser", "TorBrowser", "Data", "Browser"}},
+		{"palemoon", []string{".moonchild productions", "pale moon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.browser, func(t *testing.T) {
+			tempDir := t.TempDir()
+			t.Setenv("HOME", tempDir)
+			baseDir := filepath.Join(append([]string{tempDir}, tt.baseDir...)...)
+			profileDir := filepath.Join(baseDir, "abcd.default")
+			if err := os.MkdirAll(profileDir, 0755); err != nil {
+				t.Fatalf("Failed to create profile dir: %v", err)
+			}
+			placesPath := filepath.Join(profileDir, "places.sqlite")
+			if err := os.WriteFile(placesPath, nil, 0644); err != nil {
+				t.Fatalf("Failed to create places.sqlite: %v", err)
+			}
+			cfg := ini.Empty()
+			section, _ := cfg.NewSection("Profile0")
+			section.NewKey("Name", "default")
+			section.NewKey("Path", "abcd.default")
+			section.NewKey("IsRelative", "1")
+			cfg.SaveTo(filepath.Join(baseDir, "profiles.ini"))
+
+			b, ok := All()[tt.browser].(*FirefoxBrowser)
+			if !ok {
+				t.Fatalf("%s should be a *FirefoxBrowser", tt.browser)
+			}
+			paths, err := b.GetHistoryPaths()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := history.HistoryPathEntry{Profile: "Profile0", ProfileName: "default", Path: placesPath}
+			if len(paths) != 1 || paths[0] != expected {
+				t.Errorf("Expected [%v], got %v", expected, paths)
+			}
+		})
 	}
 }
 
@@ -81,12 +189,13 @@ func TestFirefoxBrowser_ExtractHistory(t *testing.T) {
             id INTEGER PRIMARY KEY, 
             place_id INTEGER, 
             visit_date INTEGER, 
-            visit_type INTEGER
+            visit_type INTEGER,
+            from_visit INTEGER
         );
         INSERT INTO moz_places VALUES (1, 'https://test.com', 'Test', 2, 1);
         INSERT INTO moz_places VALUES (2, 'https://example.com', NULL, 1, 0);
-        INSERT INTO moz_historyvisits VALUES (1, 1, ?, 1);
-        INSERT INTO moz_historyvisits VALUES (2, 2, ?, 2);
+        INSERT INTO moz_historyvisits VALUES (1, 1, ?, 1, 0);
+        INSERT INTO moz_historyvisits VALUES (2, 2, ?, 5, 1);
     `, time.Now().Add(-1*time.Hour).UnixMicro(), time.Now().UnixMicro())
 	if err != nil {
 		t.Fatalf("Failed to setup test data: %v", err)
@@ -96,7 +205,7 @@ func TestFirefoxBrowser_ExtractHistory(t *testing.T) {
 	startTime := time.Now().Add(-2 * time.Hour)
 	endTime := time.Now().Add(time.Hour)
 
-	entries, err := fb.ExtractHistory(dbPath, profile, startTime, endTime, false)
+	entries, err := history.Collect(fb.ExtractHistory(context.Background(), dbPath, profile, startTime, endTime, false))
 	if err != nil {
 		t.Fatalf("ExtractHistory failed: %v", err)
 	}
@@ -113,4 +222,60 @@ func TestFirefoxBrowser_ExtractHistory(t *testing.T) {
 			t.Errorf("Timestamp %v outside of range %v - %v", entry.Timestamp, startTime, endTime)
 		}
 	}
+	if len(entries) == 2 && (entries[0].VisitID != 2 || entries[0].ReferrerVisitID != 1 || entries[0].ReferrerURL != "https://test.com") {
+		t.Errorf("Expected the redirect target to be referred by visit 1, got %+v", entries[0])
+	}
+	if len(entries) == 2 && (entries[0].VisitType != history.VisitRedirectPermanent || entries[0].NativeVisitType != "TRANSITION_REDIRECT_PERMANENT") {
+		t.Errorf("Expected a permanent redirect visit, got %q (%q)", entries[0].VisitType, entries[0].NativeVisitType)
+	}
+}
+
+func TestFirefoxBrowser_ExtractHistoryDuration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "places.sqlite")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	base := time.Now().Add(-3 * time.Hour).Truncate(time.Microsecond)
+	_, err = db.Exec(`
+        CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, typed INTEGER);
+        CREATE TABLE moz_historyvisits (id INTEGER PRIMARY KEY, place_id INTEGER, visit_date INTEGER, visit_type INTEGER, from_visit INTEGER);
+        INSERT INTO moz_places VALUES (1, 'https://go.dev/', 'Go', 1, 1);
+        INSERT INTO moz_places VALUES (2, 'https://go.dev/doc/', 'Docs', 1, 0);
+        INSERT INTO moz_places VALUES (3, 'https://ads.example.com/frame', NULL, 1, 0);
+        INSERT INTO moz_places VALUES (4, 'https://pkg.go.dev/', 'Packages', 1, 0);
+        INSERT INTO moz_historyvisits VALUES (1, 1, ?, 2, 0);
+        INSERT INTO moz_historyvisits VALUES (2, 3, ?, 4, 1);
+        INSERT INTO moz_historyvisits VALUES (3, 2, ?, 1, 1);
+        INSERT INTO moz_historyvisits VALUES (4, 4, ?, 1, 3);
+    `, base.UnixMicro(), base.Add(10*time.Second).UnixMicro(), base.Add(time.Minute).UnixMicro(), base.Add(2*time.Hour).UnixMicro())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	fb := &FirefoxBrowser{}
+	entries, err := history.Collect(fb.ExtractHistory(context.Background(), dbPath, "Default", base.Add(-time.Hour), time.Now(), false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	durations := map[string]time.Duration{}
+	for _, entry := range entries {
+		durations[entry.URL] = entry.Duration
+	}
+	expected := map[string]time.Duration{
+		"https://go.dev/":               time.Minute, // The embedded frame visit does not end the visit.
+		"https://ads.example.com/frame": 0,
+		"https://go.dev/doc/":           0, // Next visit is beyond geckoMaxDwell.
+		"https://pkg.go.dev/":           0, // Latest visit.
+	}
+	if len(durations) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), durations)
+	}
+	for url, want := range expected {
+		if durations[url] != want {
+			t.Errorf("Duration of %s = %v, want %v", url, durations[url], want)
+		}
+	}
 }
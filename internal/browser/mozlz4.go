@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// mozLz4Magic starts every mozLz4 file: Firefox's LZ4 block format with a header holding the
+// decompressed size, used for jsonlz4 session and bookmark backup files.
+var mozLz4Magic = []byte("mozLz40\x00")
+
+// maxMozLz4Size bounds the decompressed size a mozLz4 header may declare. Session and bookmark
+// backups are far smaller; the limit keeps a corrupt header from forcing a huge allocation.
+const maxMozLz4Size = 256 << 20
+
+// readMozLz4File reads and decompresses a mozLz4 file.
+func readMozLz4File(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMozLz4(data)
+}
+
+// decodeMozLz4 decompresses the contents of a mozLz4 file.
+func decodeMozLz4(data []byte) ([]byte, error) {
+	headerSize := len(mozLz4Magic) + 4
+	if len(data) < headerSize || !bytes.Equal(data[:len(mozLz4Magic)], mozLz4Magic) {
+		return nil, fmt.Errorf("not a mozLz4 file")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(mozLz4Magic):headerSize]))
+	// Each input byte expands to at most 255 output bytes.
+	src := data[headerSize:]
+	if size > len(src)*255 || size > maxMozLz4Size {
+		return nil, fmt.Errorf("mozLz4: declared size %d is not possible for %d bytes of input", size, len(src))
+	}
+	return decompressLZ4Block(src, size)
+}
+
+// decompressLZ4Block decompresses a raw LZ4 block whose decompressed size is known. The output
+// grows as it is decoded rather than being allocated from the declared size.
+func decompressLZ4Block(src []byte, size int) ([]byte, error) {
+	var dst []byte
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		literals, n, err := lz4Length(src[i:], int(token>>4))
+		if err != nil {
+			return nil, err
+		}
+		i += n
+		if literals > len(src)-i {
+			return nil, fmt.Errorf("lz4: literals overrun input")
+		}
+		if literals > size-len(dst) {
+			return nil, fmt.Errorf("lz4: output exceeds declared size %d", size)
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+		if i == len(src) {
+			break // The last sequence holds only literals.
+		}
+
+		if len(src)-i < 2 {
+			return nil, fmt.Errorf("lz4: truncated match offset")
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i:]))
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, fmt.Errorf("lz4: invalid match offset %d", offset)
+		}
+		matchLength, n, err := lz4Length(src[i:], int(token&0x0F))
+		if err != nil {
+			return nil, err
+		}
+		i += n
+		if matchLength+4 > size-len(dst) {
+			return nil, fmt.Errorf("lz4: output exceeds declared size %d", size)
+		}
+		// Matches may overlap the bytes they produce, so copy byte by byte.
+		start := len(dst) - offset
+		for j := 0; j < matchLength+4; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+	if len(dst) != size {
+		return nil, fmt.Errorf("lz4: decompressed %d bytes, expected %d", len(dst), size)
+	}
+	return dst, nil
+}
+
+// lz4Length completes a literal or match length from its token nibble, reading the extra
+// length bytes that follow a nibble of 15. It returns the length and the bytes consumed.
+func lz4Length(src []byte, nibble int) (int, int, error) {
+	length, n := nibble, 0
+	if nibble != 15 {
+		return length, n, nil
+	}
+	for {
+		if n >= len(src) {
+			return 0, 0, fmt.Errorf("lz4: truncated length")
+		}
+		b := src[n]
+		n++
+		length += int(b)
+		if b != 255 {
+			return length, n, nil
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// encodeMozLz4 wraps data in a mozLz4 file holding a single literal-only LZ4 sequence.
+func encodeMozLz4(data []byte) []byte {
+	out := append([]byte{}, mozLz4Magic...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+	if len(data) < 15 {
+		out = append(out, byte(len(data)<<4))
+	} else {
+		out = append(out, 0xF0)
+		n := len(data) - 15
+		for ; n >= 255; n -= 255 {
+			out = append(out, 255)
+		}
+		out = append(out, byte(n))
+	}
+	return append(out, data...)
+}
+
+// writeMozLz4File writes data to path as a mozLz4 file.
+func writeMozLz4File(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, encodeMozLz4(data), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestDecodeMozLz4(t *testing.T) {
+	t.Run("Literals", func(t *testing.T) {
+		data := []byte(strings.Repeat("0123456789", 60))
+		got, err := decodeMozLz4(encodeMozLz4(data))
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("decodeMozLz4 = %q, %v; want %q", got, err, data)
+		}
+	})
+
+	t.Run("OverlappingMatch", func(t *testing.T) {
+		// "abc", then a 9 byte match 3 bytes back, then the literal "x".
+		block := []byte{0x35, 'a', 'b', 'c', 0x03, 0x00, 0x10, 'x'}
+		file := append(append([]byte{}, mozLz4Magic...), 13, 0, 0, 0)
+		got, err := decodeMozLz4(append(file, block...))
+		if err != nil || string(got) != "abcabcabcabcx" {
+			t.Errorf("decodeMozLz4 = %q, %v; want %q", got, err, "abcabcabcabcx")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, data := range map[string][]byte{
+			"BadMagic":    []byte("notLz40\x00\x01\x00\x00\x00\x10x"),
+			"WrongSize":   append(append([]byte{}, mozLz4Magic...), 5, 0, 0, 0, 0x10, 'x'),
+			"BadOffset":   append(append([]byte{}, mozLz4Magic...), 8, 0, 0, 0, 0x10, 'x', 0x09, 0x00),
+			"Truncated":   append(append([]byte{}, mozLz4Magic...), 4, 0, 0, 0, 0x40, 'x'),
+			"ShortHeader": []byte("mozLz40"),
+		} {
+			if _, err := decodeMozLz4(data); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+	t.Run("ImpossibleSize", func(t *testing.T) {
+		// A 16 byte file cannot decompress to the 4 GiB its header declares.
+		file := append(append([]byte{}, mozLz4Magic...), 0xFF, 0xFF, 0xFF, 0xFF, 0x30, 'a', 'b', 'c')
+		if len(file) != 16 {
+			t.Fatalf("test file is %d bytes, want 16", len(file))
+		}
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err := decodeMozLz4(file)
+		runtime.ReadMemStats(&after)
+		if err == nil || !strings.Contains(err.Error(), "declared size") {
+			t.Errorf("decodeMozLz4 error = %v, want a declared size error", err)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("decodeMozLz4 allocated %d bytes before rejecting the header", allocated)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// qutebrowserHistoryQuery is the SQL query for retrieving qutebrowser history entries. Every
+// visit is a row of the History table with its time in seconds since the Unix epoch, so the
+// visit count is derived from the other rows for the same URL.
+const qutebrowserHistoryQuery = `
+	SELECT
+		visit.url,
+		visit.title,
+		(SELECT COUNT(*) FROM History AS other WHERE other.url = visit.url) AS visit_count,
+		CASE visit.redirect
+			WHEN 1 THEN 'REDIRECT'
+			ELSE 'VISIT'
+		END AS visit_type_desc,
+		visit.atime
+	FROM History AS visit
+	WHERE visit.atime >= ? AND visit.atime <= ?
+	ORDER BY visit.atime DESC`
+
+// qutebrowserVisitTypes maps the visit types named by qutebrowserHistoryQuery to normalized
+// visit types. qutebrowser does not record whether a redirect was permanent.
+var qutebrowserVisitTypes = map[string]history.VisitType{
+	"VISIT":    history.VisitLink,
+	"REDIRECT": history.VisitRedirectTemporary,
+}
+
+// QutebrowserBrowser implements the Browser interface for qutebrowser.
+type QutebrowserBrowser struct {
+	Descriptor Descriptor
+}
+
+// NewQutebrowserBrowser creates a new instance of QutebrowserBrowser.
+func NewQutebrowserBrowser() Browser {
+	return &QutebrowserBrowser{Descriptor: qutebrowserDescriptor}
+}
+
+// GetHistoryPaths retrieves collection of paths to qutebrowser's history database files.
+func (qb *QutebrowserBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return qb.GetHistoryPathsIn(CurrentEnvironment())
+}
+
+// GetHistoryPathsIn retrieves the history database paths from every qutebrowser data directory in env.
+func (qb *QutebrowserBrowser) GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error) {
+	return qb.Descriptor.historyPaths(env, qb.getPaths)
+}
+
+// GetHistoryPathsFrom retrieves the history database path for a qutebrowser data directory,
+// such as one passed to qutebrowser with --basedir.
+func (qb *QutebrowserBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return qb.getPaths(dir)
+}
+
+// getPaths returns the data directory's history.sqlite, also looking in the data/ subdirectory
+// that --basedir layouts and Windows installs use.
+func (qb *QutebrowserBrowser) getPaths(dir string) ([]history.HistoryPathEntry, error) {
+	for _, candidate := range []string{dir, filepath.Join(dir, "data")} {
+		historyPath := filepath.Join(candidate, QutebrowserHistoryFile)
+		if _, err := os.Stat(historyPath); err == nil {
+			return []history.HistoryPathEntry{{Profile: "Default", ProfileName: "Default", Path: historyPath}}, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// ExtractHistory extracts qutebrowser history entries within the given time range.
+func (qb *QutebrowserBrowser) ExtractHistory(ctx context.Context, historyDBPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to open qutebrowser history database at %s: %v", historyDBPath, err))
+			return
+		}
+		defer db.Close()
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Querying qutebrowser history from %s, start: %v, end: %v\n", historyDBPath, startTime, endTime)
+		}
+		rows, err := db.QueryContext(ctx, qutebrowserHistoryQuery, startTime.Unix(), endTime.Unix())
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to query qutebrowser history from %s: %v", historyDBPath, err))
+			return
+		}
+		defer rows.Close()
+
+		count := 0
+		for rows.Next() {
+			var pageURL, pageVisitType string
+			var pageTitle sql.NullString
+			var pageVisitCount int
+			var visitTimestamp int64
+			if err := rows.Scan(
+				&pageURL,
+				&pageTitle,
+				&pageVisitCount,
+				&pageVisitType,
+				&visitTimestamp); err != nil {
+				yield(history.HistoryEntry{}, fmt.Errorf("failed to scan qutebrowser history row from %s: %v", historyDBPath, err))
+				return
+			}
+			count++
+			if !yield(history.HistoryEntry{
+				URL:             pageURL,
+				Title:           pageTitle.String,
+				VisitCount:      pageVisitCount,
+				VisitType:       normalizeVisitType(qutebrowserVisitTypes, pageVisitType),
+				NativeVisitType: pageVisitType,
+				Timestamp:       time.Unix(visitTimestamp, 0),
+				Profile:         profile,
+			}, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("error iterating qutebrowser history rows from %s: %v", historyDBPath, err))
+			return
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Retrieved %d entries from qutebrowser\n", count)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestQutebrowserBrowser_GetHistoryPathsFrom(t *testing.T) {
+	qb := &QutebrowserBrowser{}
+	basedir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(basedir, "data"), 0755); err != nil {
+		t.Fatalf("Failed to create data dir: %v", err)
+	}
+	historyPath := filepath.Join(basedir, "data", "history.sqlite")
+	if err := os.WriteFile(historyPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create history.sqlite: %v", err)
+	}
+
+	for _, dir := range []string{basedir, filepath.Join(basedir, "data")} {
+		paths, err := qb.GetHistoryPathsFrom(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", dir, err)
+		}
+		if len(paths) != 1 || paths[0].Path != historyPath || paths[0].Profile != "Default" {
+			t.Errorf("Unexpected paths for %s: %v", dir, paths)
+		}
+	}
+
+	if _, err := qb.GetHistoryPathsFrom(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("Expected os.ErrNotExist for empty dir, got %v", err)
+	}
+}
+
+func TestQutebrowserBrowser_ExtractHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "history.sqlite")
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	visitTime := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+	_, err = db.Exec(`
+        CREATE TABLE History (url TEXT NOT NULL, title TEXT NOT NULL, atime INTEGER NOT NULL, redirect INTEGER NOT NULL);
+        CREATE TABLE CompletionHistory (url TEXT PRIMARY KEY, title TEXT NOT NULL, last_atime INTEGER NOT NULL);
+        INSERT INTO History VALUES ('http://test.com', '', ?, 1);
+        INSERT INTO History VALUES ('https://test.com/', 'Test', ?, 0);
+        INSERT INTO History VALUES ('https://test.com/', 'Test', ?, 0);
+    `, visitTime.Add(-time.Minute).Unix(), visitTime.Add(-time.Minute).Unix(), visitTime.Unix())
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	qb := &QutebrowserBrowser{}
+	entries, err := history.Collect(qb.ExtractHistory(context.Background(), dbPath, "Default", time.Now().Add(-2*time.Hour), time.Now(), false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].URL != "https://test.com/" || entries[0].VisitCount != 2 || entries[0].VisitType != history.VisitLink || entries[0].NativeVisitType != "VISIT" || !entries[0].Timestamp.Equal(visitTime) {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+	redirects := 0
+	for _, entry := range entries {
+		if entry.NativeVisitType == "REDIRECT" && entry.VisitType == history.VisitRedirectTemporary {
+			redirects++
+		}
+	}
+	if redirects != 1 {
+		t.Errorf("Expected 1 redirect visit, got %d", redirects)
+	}
+}
@@ -0,0 +1,468 @@
+package browser
+
+import "sync"
+
+// registration pairs a browser name with a factory for its implementation.
+type registration struct {
+	name    string
+	factory func() Browser
+	// descriptor and historyFile are set for descriptor-driven browsers and used to recognise
+	// their profiles outside the default locations.
+	descriptor  *Descriptor
+	historyFile string
+}
+
+// registry holds every known browser in registration order, which is also the default extraction
+// order. registryMu guards it, as Register may be called while browsers are being looked up.
+var (
+	registryMu sync.RWMutex
+	registry   = defaultRegistry()
+)
+
+// registrations returns a snapshot of the registry.
+func registrations() []registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]registration(nil), registry...)
+}
+
+// chromeDescriptor describes Google Chrome stable.
+var chromeDescriptor = Descriptor{
+	Name:    "chrome",
+	Channel: "stable",
+	UserDataDirs: map[string][]string{
+		"windows": {"$LOCALAPPDATA/Google/Chrome/User Data"},
+		"darwin":  {"$HOME/Library/Application Support/Google/Chrome"},
+		"linux":   {"$HOME/.config/google-chrome"},
+	},
+	Flatpak: []string{"$HOME/.var/app/com.google.Chrome/config/google-chrome"},
+	Executables: map[string][]string{
+		"windows": {"$PROGRAMFILES/Google/Chrome/Application/chrome.exe"},
+		"darwin":  {"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome"},
+		"linux":   {"google-chrome", "google-chrome-stable"},
+	},
+}
+
+// chromiumDescriptors lists the Chromium-family browsers supported out of the box.
+var chromiumDescriptors = []Descriptor{
+	chromeDescriptor,
+	{
+		Name:    "chrome-beta",
+		Channel: "beta",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Google/Chrome Beta/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Google/Chrome Beta"},
+			"linux":   {"$HOME/.config/google-chrome-beta"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Google/Chrome Beta/Application/chrome.exe"},
+			"darwin":  {"/Applications/Google Chrome Beta.app/Contents/MacOS/Google Chrome Beta"},
+			"linux":   {"google-chrome-beta"},
+		},
+	},
+	{
+		// The Dev channel is packaged as google-chrome-unstable on Linux.
+		Name:    "chrome-dev",
+		Channel: "dev",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Google/Chrome Dev/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Google/Chrome Dev"},
+			"linux":   {"$HOME/.config/google-chrome-unstable"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Google/Chrome Dev/Application/chrome.exe"},
+			"darwin":  {"/Applications/Google Chrome Dev.app/Contents/MacOS/Google Chrome Dev"},
+			"linux":   {"google-chrome-unstable"},
+		},
+	},
+	{
+		// Canary installs side by side ("SxS") with the other channels on Windows.
+		Name:    "chrome-canary",
+		Channel: "canary",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Google/Chrome SxS/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Google/Chrome Canary"},
+			"linux":   {"$HOME/.config/google-chrome-canary"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Google/Chrome SxS/Application/chrome.exe"},
+			"darwin":  {"/Applications/Google Chrome Canary.app/Contents/MacOS/Google Chrome Canary"},
+			"linux":   {"google-chrome-canary"},
+		},
+	},
+	{
+		Name: "edge",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Microsoft/Edge/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Microsoft Edge"},
+			"linux":   {"$HOME/.config/microsoft-edge"},
+		},
+		Flatpak: []string{"$HOME/.var/app/com.microsoft.Edge/config/microsoft-edge"},
+		Executables: map[string][]string{
+			"windows": {"${PROGRAMFILES(X86)}/Microsoft/Edge/Application/msedge.exe"},
+			"darwin":  {"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge"},
+			"linux":   {"microsoft-edge", "microsoft-edge-stable"},
+		},
+	},
+	{
+		Name: "brave",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/BraveSoftware/Brave-Browser/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/BraveSoftware/Brave-Browser"},
+			"linux":   {"$HOME/.config/BraveSoftware/Brave-Browser"},
+		},
+		Flatpak: []string{"$HOME/.var/app/com.brave.Browser/config/BraveSoftware/Brave-Browser"},
+		Snap:    []string{"$HOME/snap/brave/current/.config/BraveSoftware/Brave-Browser"},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/BraveSoftware/Brave-Browser/Application/brave.exe"},
+			"darwin":  {"/Applications/Brave Browser.app/Contents/MacOS/Brave Browser"},
+			"linux":   {"brave-browser", "brave"},
+		},
+	},
+	{
+		Name: "chromium",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Chromium/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Chromium"},
+			"linux":   {"$HOME/.config/chromium"},
+		},
+		Flatpak: []string{"$HOME/.var/app/org.chromium.Chromium/config/chromium"},
+		Snap:    []string{"$HOME/snap/chromium/common/chromium"},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Chromium/Application/chrome.exe"},
+			"darwin":  {"/Applications/Chromium.app/Contents/MacOS/Chromium"},
+			"linux":   {"chromium", "chromium-browser"},
+		},
+	},
+	{
+		Name: "vivaldi",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Vivaldi/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Vivaldi"},
+			"linux":   {"$HOME/.config/vivaldi"},
+		},
+		Flatpak: []string{"$HOME/.var/app/com.vivaldi.Vivaldi/config/vivaldi"},
+		Snap:    []string{"$HOME/snap/vivaldi/current/.config/vivaldi"},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Vivaldi/Application/vivaldi.exe"},
+			"darwin":  {"/Applications/Vivaldi.app/Contents/MacOS/Vivaldi"},
+			"linux":   {"vivaldi", "vivaldi-stable"},
+		},
+	},
+	{
+		// Opera stores a single profile directly in its user data directory.
+		Name: "opera",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/Opera Software/Opera Stable"},
+			"darwin":  {"$HOME/Library/Application Support/com.operasoftware.Opera"},
+			"linux":   {"$HOME/.config/opera"},
+		},
+		Flatpak: []string{"$HOME/.var/app/com.opera.Opera/config/opera"},
+		Snap:    []string{"$HOME/snap/opera/current/.config/opera"},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Programs/Opera/opera.exe"},
+			"darwin":  {"/Applications/Opera.app/Contents/MacOS/Opera"},
+			"linux":   {"opera"},
+		},
+	},
+	{
+		// Opera GX is not available on Linux.
+		Name: "opera-gx",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/Opera Software/Opera GX Stable"},
+			"darwin":  {"$HOME/Library/Application Support/com.operasoftware.OperaGX"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Programs/Opera GX/opera.exe"},
+			"darwin":  {"/Applications/Opera GX.app/Contents/MacOS/Opera"},
+		},
+	},
+	{
+		Name: "yandex",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Yandex/YandexBrowser/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Yandex/YandexBrowser"},
+			"linux":   {"$HOME/.config/yandex-browser"},
+		},
+		Flatpak: []string{"$HOME/.var/app/ru.yandex.Browser/config/yandex-browser"},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Yandex/YandexBrowser/Application/browser.exe"},
+			"darwin":  {"/Applications/Yandex.app/Contents/MacOS/Yandex"},
+			"linux":   {"yandex-browser", "yandex-browser-stable"},
+		},
+	},
+	{
+		Name: "thorium",
+		UserDataDirs: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Thorium/User Data"},
+			"darwin":  {"$HOME/Library/Application Support/Thorium"},
+			"linux":   {"$HOME/.config/thorium"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$LOCALAPPDATA/Thorium/Application/thorium.exe"},
+			"darwin":  {"/Applications/Thorium.app/Contents/MacOS/Thorium"},
+			"linux":   {"thorium-browser", "thorium"},
+		},
+	},
+}
+
+// firefoxDescriptor describes Mozilla Firefox.
+var firefoxDescriptor = Descriptor{
+	Name: "firefox",
+	UserDataDirs: map[string][]string{
+		"windows": {"$APPDATA/Mozilla/Firefox"},
+		"darwin":  {"$HOME/Library/Application Support/Firefox"},
+		"linux":   {"$HOME/.mozilla/firefox"},
+	},
+	Flatpak: []string{"$HOME/.var/app/org.mozilla.firefox/.mozilla/firefox", "$HOME/.var/app/org.mozilla.firefox/config/mozilla/firefox"},
+	Snap:    []string{"$HOME/snap/firefox/common/.mozilla/firefox"},
+	Executables: map[string][]string{
+		"windows": {"$PROGRAMFILES/Mozilla Firefox/firefox.exe"},
+		"darwin":  {"/Applications/Firefox.app/Contents/MacOS/firefox"},
+		"linux":   {"firefox"},
+	},
+}
+
+// geckoDescriptors lists the Gecko-family browsers supported out of the box. Each user
+// data directory is expected to contain a profiles.ini file.
+var geckoDescriptors = []Descriptor{
+	firefoxDescriptor,
+	{
+		Name: "librewolf",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/librewolf"},
+			"darwin":  {"$HOME/Library/Application Support/librewolf"},
+			"linux":   {"$HOME/.librewolf"},
+		},
+		Flatpak: []string{"$HOME/.var/app/io.gitlab.librewolf-community/.librewolf"},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/LibreWolf/librewolf.exe"},
+			"darwin":  {"/Applications/LibreWolf.app/Contents/MacOS/librewolf"},
+			"linux":   {"librewolf"},
+		},
+	},
+	{
+		Name: "waterfox",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/Waterfox"},
+			"darwin":  {"$HOME/Library/Application Support/Waterfox"},
+			"linux":   {"$HOME/.waterfox"},
+		},
+		Flatpak: []string{"$HOME/.var/app/net.waterfox.waterfox/.waterfox"},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Waterfox/waterfox.exe"},
+			"darwin":  {"/Applications/Waterfox.app/Contents/MacOS/waterfox"},
+			"linux":   {"waterfox"},
+		},
+	},
+	{
+		Name: "floorp",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/Floorp"},
+			"darwin":  {"$HOME/Library/Application Support/Floorp"},
+			"linux":   {"$HOME/.floorp"},
+		},
+		Flatpak: []string{"$HOME/.var/app/one.ablaze.floorp/.floorp"},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Ablaze Floorp/floorp.exe"},
+			"darwin":  {"/Applications/Floorp.app/Contents/MacOS/floorp"},
+			"linux":   {"floorp"},
+		},
+	},
+	{
+		Name: "zen",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/zen"},
+			"darwin":  {"$HOME/Library/Application Support/zen"},
+			"linux":   {"$HOME/.zen"},
+		},
+		Flatpak: []string{"$HOME/.var/app/app.zen_browser.zen/.zen"},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Zen Browser/zen.exe"},
+			"darwin":  {"/Applications/Zen.app/Contents/MacOS/zen"},
+			"linux":   {"zen", "zen-browser"},
+		},
+	},
+	{
+		// Tor Browser is a portable install; these are the default extraction and launcher locations.
+		Name: "tor",
+		UserDataDirs: map[string][]string{
+			"windows": {"$USERPROFILE/Desktop/Tor Browser/Browser/TorBrowser/Data/Browser"},
+			"darwin":  {"$HOME/Library/Application Support/TorBrowser-Data/Browser"},
+			"linux": {
+				"$HOME/.local/share/torbrowser/tbb/x86_64/tor-browser/Browser/TorBrowser/Data/Browser",
+				"$HOME/tor-browser/Browser/TorBrowser/Data/Browser",
+			},
+		},
+		Flatpak: []string{"$HOME/.var/app/org.torproject.torbrowser-launcher/data/torbrowser/tbb/x86_64/tor-browser/Browser/TorBrowser/Data/Browser"},
+		Executables: map[string][]string{
+			"windows": {"$USERPROFILE/Desktop/Tor Browser/Browser/firefox.exe"},
+			"darwin":  {"/Applications/Tor Browser.app/Contents/MacOS/firefox"},
+			"linux":   {"torbrowser-launcher", "tor-browser"},
+		},
+	},
+	{
+		Name: "palemoon",
+		UserDataDirs: map[string][]string{
+			"windows": {"$APPDATA/Moonchild Productions/Pale Moon"},
+			"darwin":  {"$HOME/Library/Application Support/Pale Moon"},
+			"linux":   {"$HOME/.moonchild productions/pale moon"},
+		},
+		Executables: map[string][]string{
+			"windows": {"$PROGRAMFILES/Pale Moon/palemoon.exe"},
+			"darwin":  {"/Applications/Pale Moon.app/Contents/MacOS/palemoon"},
+			"linux":   {"palemoon"},
+		},
+	},
+}
+
+// safariDescriptor describes Apple Safari, whose default profile lives in ~/Library/Safari and
+// whose sandboxed container holds the same layout on recent macOS releases.
+var safariDescriptor = Descriptor{
+	Name: "safari",
+	UserDataDirs: map[string][]string{
+		"darwin": {
+			"$HOME/Library/Safari",
+			"$HOME/Library/Containers/com.apple.Safari/Data/Library/Safari",
+		},
+	},
+	Executables: map[string][]string{
+		"darwin": {"/Applications/Safari.app/Contents/MacOS/Safari"},
+	},
+}
+
+// epiphanyDescriptor describes GNOME Web, which keeps its default profile in the XDG data directory.
+var epiphanyDescriptor = Descriptor{
+	Name: "epiphany",
+	UserDataDirs: map[string][]string{
+		"linux": {"$XDG_DATA_HOME/epiphany"},
+	},
+	Flatpak: []string{"$HOME/.var/app/org.gnome.Epiphany/data/epiphany"},
+	Executables: map[string][]string{
+		"linux": {"epiphany", "epiphany-browser"},
+	},
+}
+
+// falkonDescriptor describes Falkon, whose user data directories hold one folder per profile.
+var falkonDescriptor = Descriptor{
+	Name: "falkon",
+	UserDataDirs: map[string][]string{
+		"windows": {"$APPDATA/falkon/profiles"},
+		"darwin":  {"$HOME/Library/Application Support/falkon/profiles"},
+		"linux":   {"$XDG_CONFIG_HOME/falkon/profiles"},
+	},
+	Flatpak: []string{"$HOME/.var/app/org.kde.falkon/config/falkon/profiles"},
+	Executables: map[string][]string{
+		"windows": {"$PROGRAMFILES/Falkon/falkon.exe"},
+		"darwin":  {"/Applications/Falkon.app/Contents/MacOS/Falkon"},
+		"linux":   {"falkon"},
+	},
+}
+
+// qutebrowserDescriptor describes qutebrowser's data directory.
+var qutebrowserDescriptor = Descriptor{
+	Name: "qutebrowser",
+	UserDataDirs: map[string][]string{
+		"windows": {"$APPDATA/qutebrowser/data"},
+		"darwin":  {"$HOME/Library/Application Support/qutebrowser"},
+		"linux":   {"$XDG_DATA_HOME/qutebrowser"},
+	},
+	Flatpak: []string{"$HOME/.var/app/org.qutebrowser.qutebrowser/data/qutebrowser"},
+	Executables: map[string][]string{
+		"windows": {"$PROGRAMFILES/qutebrowser/qutebrowser.exe"},
+		"darwin":  {"/Applications/qutebrowser.app/Contents/MacOS/qutebrowser"},
+		"linux":   {"qutebrowser"},
+	},
+}
+
+func defaultRegistry() []registration {
+	var entries []registration
+	for _, d := range chromiumDescriptors {
+		entries = append(entries, chromiumRegistration(d))
+	}
+	for _, d := range geckoDescriptors {
+		entries = append(entries, geckoRegistration(d))
+	}
+	entries = append(entries,
+		fileRegistration(&safariDescriptor, NewSafariBrowser, SafariHistoryFile),
+		fileRegistration(&epiphanyDescriptor, NewEpiphanyBrowser, EpiphanyHistoryFile),
+		fileRegistration(&falkonDescriptor, NewFalkonBrowser, FalkonHistoryFile),
+		fileRegistration(&qutebrowserDescriptor, NewQutebrowserBrowser, QutebrowserHistoryFile),
+	)
+	return entries
+}
+
+// fileRegistration registers a browser with its own implementation and history database file name.
+func fileRegistration(d *Descriptor, factory func() Browser, historyFile string) registration {
+	return registration{
+		name:        d.Name,
+		factory:     factory,
+		descriptor:  d,
+		historyFile: historyFile,
+	}
+}
+
+func chromiumRegistration(d Descriptor) registration {
+	return registration{
+		name:        d.Name,
+		factory:     func() Browser { return NewChromiumBrowser(d) },
+		descriptor:  &d,
+		historyFile: ChromiumHistoryFile,
+	}
+}
+
+func geckoRegistration(d Descriptor) registration {
+	return registration{
+		name:        d.Name,
+		factory:     func() Browser { return NewGeckoBrowser(d) },
+		descriptor:  &d,
+		historyFile: GeckoHistoryFile,
+	}
+}
+
+// Register adds a browser to the registry, replacing any existing browser with the same name.
+func Register(name string, factory func() Browser) {
+	register(registration{name: name, factory: factory})
+}
+
+// RegisterChromium adds a Chromium-based browser described by d to the registry.
+func RegisterChromium(d Descriptor) {
+	register(chromiumRegistration(d))
+}
+
+// RegisterGecko adds a Gecko-based browser described by d to the registry.
+func RegisterGecko(d Descriptor) {
+	register(geckoRegistration(d))
+}
+
+func register(entry registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, r := range registry {
+		if r.name == entry.name {
+			registry[i] = entry
+			return
+		}
+	}
+	registry = append(registry, entry)
+}
+
+// Names returns the names of all registered browsers in registration order.
+func Names() []string {
+	entries := registrations()
+	names := make([]string, 0, len(entries))
+	for _, r := range entries {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// All returns a new instance of every registered browser keyed by name.
+func All() map[string]Browser {
+	entries := registrations()
+	browsers := make(map[string]Browser, len(entries))
+	for _, r := range entries {
+		browsers[r.name] = r.factory()
+	}
+	return browsers
+}
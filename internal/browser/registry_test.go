@@ -0,0 +1,108 @@
+package browser
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRegisterGecko(t *testing.T) {
+	saved := registry
+	defer func() { registry = saved }()
+	registry = append([]registration(nil), registry...)
+
+	RegisterGecko(Descriptor{Name: "test-gecko"})
+	fb, ok := All()["test-gecko"].(*FirefoxBrowser)
+	if !ok {
+		t.Fatal("Registered descriptor should produce a *FirefoxBrowser")
+	}
+	if fb.Descriptor.Name != "test-gecko" {
+		t.Errorf("Expected descriptor name 'test-gecko', got %q", fb.Descriptor.Name)
+	}
+}
+
+func TestNames_DefaultOrder(t *testing.T) {
+	names := Names()
+	expected := []string{"chrome", "chrome-beta", "chrome-dev", "chrome-canary", "edge", "brave", "chromium", "vivaldi", "opera", "opera-gx", "yandex", "thorium",
+		"firefox", "librewolf", "waterfox", "floorp", "zen", "tor", "palemoon", "safari", "epiphany", "falkon", "qutebrowser"}
+	if len(names) < len(expected) {
+		t.Fatalf("Expected at least %d names, got %v", len(expected), names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("Names()[%d] = %q, want %q", i, names[i], name)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	browsers := All()
+	if len(browsers) != len(Names()) {
+		t.Errorf("Expected %d browsers, got %d", len(Names()), len(browsers))
+	}
+	if _, ok := browsers["edge"].(*ChromiumBrowser); !ok {
+		t.Error("edge should be a *ChromiumBrowser")
+	}
+	if _, ok := browsers["firefox"].(*FirefoxBrowser); !ok {
+		t.Error("firefox should be a *FirefoxBrowser")
+	}
+}
+
+func TestRegisterChromium(t *testing.T) {
+	saved := registry
+	defer func() { registry = saved }()
+	registry = append([]registration(nil), registry...)
+
+	RegisterChromium(Descriptor{Name: "test-fork"})
+	browsers := All()
+	cb, ok := browsers["test-fork"].(*ChromiumBrowser)
+	if !ok {
+		t.Fatal("Registered descriptor should produce a *ChromiumBrowser")
+	}
+	if cb.Descriptor.Name != "test-fork" {
+		t.Errorf("Expected descriptor name 'test-fork', got %q", cb.Descriptor.Name)
+	}
+
+	RegisterChromium(Descriptor{Name: "test-fork"})
+	if len(Names()) != len(saved)+1 {
+		t.Errorf("Re-registering should replace, got %d names", len(Names()))
+	}
+}
+
+func TestRegister_Concurrent(t *testing.T) {
+	saved := registry
+	defer func() { registry = saved }()
+	registry = append([]registration(nil), registry...)
+
+	// Run with -race: registering must not race with lookups.
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterChromium(Descriptor{Name: fmt.Sprintf("test-fork-%d", i)})
+		}()
+		go func() {
+			defer wg.Done()
+			Names()
+			DetectBrowser("Google/Chrome/User Data/Default/History")
+		}()
+	}
+	wg.Wait()
+	if len(Names()) != len(saved)+8 {
+		t.Errorf("Expected %d browsers after registering 8, got %d", len(saved)+8, len(Names()))
+	}
+}
+
+func TestRegistry_Executables(t *testing.T) {
+	for _, r := range registrations() {
+		if r.descriptor == nil {
+			continue
+		}
+		for goos := range r.descriptor.UserDataDirs {
+			if len(r.descriptor.Executables[goos]) == 0 {
+				t.Errorf("%s has %s user data directories but no %s executables", r.name, goos, goos)
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// safariHistoryQuery is the SQL query for retrieving Safari history entries. Safari records no
+// transition type, so the visit type is derived from the redirect linkage and request method.
+const safariHistoryQuery = `
+	SELECT
+		history_items.url,
+		history_visits.title,
+		history_items.visit_count,
+		CASE
+			WHEN history_visits.redirect_source IS NOT NULL THEN 'REDIRECT'
+			WHEN history_visits.redirect_destination IS NOT NULL THEN 'REDIRECT_SOURCE'
+			WHEN history_visits.http_non_get = 1 THEN 'FORM_SUBMIT'
+			ELSE 'VISIT'
+		END AS visit_type_desc,
+		history_visits.visit_time,
+		history_visits.id,
+		IFNULL(history_visits.redirect_source, 0),
+		IFNULL(redirect_item.url, '')
+	FROM history_visits
+	JOIN history_items ON history_items.id = history_visits.history_item
+	LEFT JOIN history_visits AS redirect_visit ON redirect_visit.id = history_visits.redirect_source
+	LEFT JOIN history_items AS redirect_item ON redirect_item.id = redirect_visit.history_item
+	WHERE history_visits.visit_time >= ? AND history_visits.visit_time <= ?
+	ORDER BY history_visits.visit_time DESC`
+
+// safariVisitTypes maps the visit types named by safariHistoryQuery to normalized visit types.
+// Safari does not record whether a redirect was permanent, and a visit that redirected elsewhere
+// was reached like any other page.
+var safariVisitTypes = map[string]history.VisitType{
+	"VISIT":           history.VisitLink,
+	"REDIRECT":        history.VisitRedirectTemporary,
+	"REDIRECT_SOURCE": history.VisitLink,
+	"FORM_SUBMIT":     history.VisitFormSubmit,
+}
+
+// coreDataEpochOffset is the number of seconds between 1970-01-01 and 2001-01-01, the Core Data reference date.
+const coreDataEpochOffset = 978307200
+
+// SafariBrowser implements the Browser interface for Apple Safari.
+type SafariBrowser struct {
+	Descriptor Descriptor
+}
+
+// NewSafariBrowser creates a new instance of SafariBrowser.
+func NewSafariBrowser() Browser {
+	return &SafariBrowser{Descriptor: safariDescriptor}
+}
+
+// GetHistoryPaths retrieves collection of paths to Safari's history database files.
+func (sb *SafariBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return sb.GetHistoryPathsIn(CurrentEnvironment())
+}
+
+// GetHistoryPathsIn retrieves the history database paths from every Safari data directory in env.
+func (sb *SafariBrowser) GetHistoryPathsIn(env Environment) ([]history.HistoryPathEntry, error) {
+	return sb.Descriptor.historyPaths(env, sb.getPaths)
+}
+
+// GetHistoryPathsFrom retrieves the history database paths for a Safari data directory or a
+// directory holding a copied History.db.
+func (sb *SafariBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return sb.getPaths(dir)
+}
+
+// getPaths collects the default profile's History.db and those of any named Safari profiles under Profiles/.
+func (sb *SafariBrowser) getPaths(dir string) ([]history.HistoryPathEntry, error) {
+	var profilePaths []history.HistoryPathEntry
+	defaultPath := filepath.Join(dir, SafariHistoryFile)
+	if _, err := os.Stat(defaultPath); err == nil {
+		profilePaths = append(profilePaths, history.HistoryPathEntry{
+			Profile:     "Default",
+			ProfileName: "Default",
+			Path:        defaultPath,
+		})
+	}
+
+	profiles, _ := os.ReadDir(filepath.Join(dir, "Profiles"))
+	for _, entry := range profiles {
+		profilePath := filepath.Join(dir, "Profiles", entry.Name(), SafariHistoryFile)
+		if _, err := os.Stat(profilePath); entry.IsDir() && err == nil {
+			profilePaths = append(profilePaths, history.HistoryPathEntry{
+				Profile:     entry.Name(),
+				ProfileName: entry.Name(),
+				Path:        profilePath,
+			})
+		}
+	}
+	if len(profilePaths) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return profilePaths, nil
+}
+
+// ExtractHistory extracts Safari history entries within the given time range.
+func (sb *SafariBrowser) ExtractHistory(ctx context.Context, historyDBPath, profile string, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		db, err := sql.Open("sqlite3", "file:"+historyDBPath+"?mode=ro")
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to open Safari history database at %s: %v", historyDBPath, err))
+			return
+		}
+		defer db.Close()
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Querying Safari history from %s, start: %v, end: %v\n", historyDBPath, startTime, endTime)
+		}
+		rows, err := db.QueryContext(ctx, safariHistoryQuery, TimeToCoreDataTime(startTime), TimeToCoreDataTime(endTime))
+		if err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to query Safari history from %s: %v", historyDBPath, err))
+			return
+		}
+		defer rows.Close()
+
+		count := 0
+		for rows.Next() {
+			var pageURL, pageVisitType, redirectURL string
+			var pageTitle sql.NullString
+			var pageVisitCount int
+			var visitTimestamp float64
+			var visitID, redirectVisitID int64
+			if err := rows.Scan(
+				&pageURL,
+				&pageTitle,
+				&pageVisitCount,
+				&pageVisitType,
+				&visitTimestamp,
+				&visitID,
+				&redirectVisitID,
+				&redirectURL); err != nil {
+				yield(history.HistoryEntry{}, fmt.Errorf("failed to scan Safari history row from %s: %v", historyDBPath, err))
+				return
+			}
+			count++
+			if !yield(history.HistoryEntry{
+				URL:             pageURL,
+				Title:           pageTitle.String,
+				VisitCount:      pageVisitCount,
+				VisitType:       normalizeVisitType(safariVisitTypes, pageVisitType),
+				NativeVisitType: pageVisitType,
+				Timestamp:       CoreDataTimeToTime(visitTimestamp),
+				// Safari only records which visit redirected to this one, not the linking page.
+				VisitID:         visitID,
+				ReferrerVisitID: redirectVisitID,
+				ReferrerURL:     redirectURL,
+				Profile:         profile,
+			}, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(history.HistoryEntry{}, fmt.Errorf("error iterating Safari history rows from %s: %v", historyDBPath, err))
+			return
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: Retrieved %d entries from Safari\n", count)
+		}
+	}
+}
+
+// TimeToCoreDataTime converts Go time.Time to a Core Data timestamp (seconds since 2001-01-01).
+func TimeToCoreDataTime(t time.Time) float64 {
+	return float64(t.UnixMicro())/1e6 - coreDataEpochOffset
+}
+
+// CoreDataTimeToTime converts a Core Data timestamp to Go time.Time, keeping microsecond precision.
+func CoreDataTimeToTime(coreDataTimestamp float64) time.Time {
+	return time.UnixMicro(int64(math.Round((coreDataTimestamp + coreDataEpochOffset) * 1e6)))
+}
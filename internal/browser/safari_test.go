@@ -0,0 +1,177 @@
+package browser
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestNewSafariBrowser(t *testing.T) {
+	browser := NewSafariBrowser()
+	if _, ok := browser.(*SafariBrowser); !ok {
+		t.Error("NewSafariBrowser should return a *SafariBrowser")
+	}
+}
+
+func TestSafariBrowser_GetHistoryPathsFrom(t *testing.T) {
+	sb := &SafariBrowser{}
+	tempDir := t.TempDir()
+
+	defaultPath := filepath.Join(tempDir, "History.db")
+	if err := os.WriteFile(defaultPath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create History.db: %v", err)
+	}
+	profileDir := filepath.Join(tempDir, "Profiles", "0F5A2C3E-1111-2222-3333-444455556666")
+	if err := os.MkdirAll(profileDir, 0755); err != nil {
+		t.Fatalf("Failed to create profile dir: %v", err)
+	}
+	profilePath := filepath.Join(profileDir, "History.db")
+	if err := os.WriteFile(profilePath, nil, 0644); err != nil {
+		t.Fatalf("Failed to create profile History.db: %v", err)
+	}
+
+	paths, err := sb.GetHistoryPathsFrom(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []history.HistoryPathEntry{
+		{Profile: "Default", ProfileName: "Default", Path: defaultPath},
+		{Profile: "0F5A2C3E-1111-2222-3333-444455556666", ProfileName: "0F5A2C3E-1111-2222-3333-444455556666", Path: profilePath},
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], paths[i])
+		}
+	}
+
+	if _, err := sb.GetHistoryPathsFrom(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("Expected os.ErrNotExist for empty dir, got %v", err)
+	}
+}
+
+func TestSafariBrowser_MacImage(t *testing.T) {
+	root := t.TempDir()
+	safariDir := filepath.Join(root, "Users", "carol", "Library", "Safari")
+	if err := os.MkdirAll(safariDir, 0755); err != nil {
+		t.Fatalf("Failed to create Safari dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(safariDir, "History.db"), nil, 0644); err != nil {
+		t.Fatalf("Failed to create History.db: %v", err)
+	}
+
+	env, err := RootedEnvironment(root, "darwin", "/Users/carol")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paths, err := NewSafariBrowser().(EnvironmentBrowser).GetHistoryPathsIn(env)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(paths) != 1 || paths[0].User != "carol" || paths[0].Path != filepath.Join(safariDir, "History.db") {
+		t.Errorf("Unexpected paths: %v", paths)
+	}
+
+	env.OS = "linux"
+	if _, err := NewSafariBrowser().(EnvironmentBrowser).GetHistoryPathsIn(env); err == nil {
+		t.Error("Expected an error for Safari on linux")
+	}
+}
+
+func TestSafariBrowser_ExtractHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "History.db")
+	profile := "Default"
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	visitTime := time.Now().Add(-1 * time.Hour).Truncate(time.Microsecond)
+	_, err = db.Exec(`
+        CREATE TABLE history_items (
+            id INTEGER PRIMARY KEY,
+            url TEXT NOT NULL UNIQUE,
+            domain_expansion TEXT NULL,
+            visit_count INTEGER NOT NULL
+        );
+        CREATE TABLE history_visits (
+            id INTEGER PRIMARY KEY,
+            history_item INTEGER NOT NULL,
+            visit_time REAL NOT NULL,
+            title TEXT NULL,
+            load_successful BOOLEAN NOT NULL DEFAULT 1,
+            http_non_get BOOLEAN NOT NULL DEFAULT 0,
+            redirect_source INTEGER NULL UNIQUE,
+            redirect_destination INTEGER NULL UNIQUE
+        );
+        INSERT INTO history_items VALUES (1, 'http://test.com', 'test', 1);
+        INSERT INTO history_items VALUES (2, 'https://test.com/', 'test', 3);
+        INSERT INTO history_items VALUES (3, 'https://old.com', 'old', 1);
+        INSERT INTO history_visits VALUES (1, 1, ?, NULL, 1, 0, NULL, 2);
+        INSERT INTO history_visits VALUES (2, 2, ?, 'Test', 1, 0, 1, NULL);
+        INSERT INTO history_visits VALUES (3, 2, ?, 'Test', 1, 1, NULL, NULL);
+        INSERT INTO history_visits VALUES (4, 3, ?, 'Old', 1, 0, NULL, NULL);
+    `, TimeToCoreDataTime(visitTime), TimeToCoreDataTime(visitTime.Add(time.Second)),
+		TimeToCoreDataTime(visitTime.Add(time.Minute)), TimeToCoreDataTime(time.Now().Add(-48*time.Hour)))
+	if err != nil {
+		t.Fatalf("Failed to setup test data: %v", err)
+	}
+
+	sb := &SafariBrowser{}
+	startTime := time.Now().Add(-2 * time.Hour)
+	endTime := time.Now().Add(time.Hour)
+
+	entries, err := history.Collect(sb.ExtractHistory(context.Background(), dbPath, profile, startTime, endTime, false))
+	if err != nil {
+		t.Fatalf("ExtractHistory failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	expected := []struct {
+		url       string
+		title     string
+		visitType history.VisitType
+		native    string
+	}{
+		{"https://test.com/", "Test", history.VisitFormSubmit, "FORM_SUBMIT"},
+		{"https://test.com/", "Test", history.VisitRedirectTemporary, "REDIRECT"},
+		{"http://test.com", "", history.VisitLink, "REDIRECT_SOURCE"},
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.URL != want.url || entry.Title != want.title || entry.VisitType != want.visitType || entry.NativeVisitType != want.native || entry.Profile != profile {
+			t.Errorf("Entry %d: got %+v, want %+v", i, entry, want)
+		}
+	}
+	if !entries[2].Timestamp.Equal(visitTime) {
+		t.Errorf("Expected timestamp %v, got %v", visitTime, entries[2].Timestamp)
+	}
+	if entries[1].VisitID != 2 || entries[1].ReferrerVisitID != 1 || entries[1].ReferrerURL != "http://test.com" {
+		t.Errorf("Expected the redirect target to be referred by its source visit, got %+v", entries[1])
+	}
+}
+
+func TestCoreDataTimeConversion(t *testing.T) {
+	reference := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := TimeToCoreDataTime(reference); got != 0 {
+		t.Errorf("Expected 0 for the Core Data reference date, got %v", got)
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	if converted := CoreDataTimeToTime(TimeToCoreDataTime(now)); !converted.Equal(now) {
+		t.Errorf("Round trip failed: got %v, want %v", converted, now)
+	}
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// searchEngine describes how a search engine encodes the query in its results URL.
+type searchEngine struct {
+	name   string   // Engine name reported on search term entries.
+	domain string   // Registrable domain label, e.g. "google" for www.google.co.uk.
+	paths  []string // Results page paths.
+	params []string // Query parameters holding the term, in order of preference.
+}
+
+// searchEngines lists the engines whose results URLs ParseSearchURL recognises.
+var searchEngines = []searchEngine{
+	{name: "google", domain: "google", paths: []string{"/search"}, params: []string{"q"}},
+	{name: "bing", domain: "bing", paths: []string{"/search"}, params: []string{"q"}},
+	{name: "duckduckgo", domain: "duckduckgo", paths: []string{"/", "/html", "/html/", "/lite", "/lite/"}, params: []string{"q"}},
+	{name: "yahoo", domain: "yahoo", paths: []string{"/search"}, params: []string{"p", "q"}},
+	{name: "youtube", domain: "youtube", paths: []string{"/results"}, params: []string{"search_query"}},
+	{name: "amazon", domain: "amazon", paths: []string{"/s"}, params: []string{"k", "field-keywords"}},
+	{name: "github", domain: "github", paths: []string{"/search"}, params: []string{"q"}},
+}
+
+// ParseSearchURL extracts the engine name and search term from a known search engine's
+// results URL. ok is false when rawURL is not such a URL or carries no term.
+func ParseSearchURL(rawURL string) (engine, term string, ok bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", "", false
+	}
+	domain := searchDomain(parsed.Hostname())
+	query := parsed.Query()
+	for _, e := range searchEngines {
+		if e.domain != domain || !slices.Contains(e.paths, parsed.Path) {
+			continue
+		}
+		for _, param := range e.params {
+			if term := strings.TrimSpace(query.Get(param)); term != "" {
+				return e.name, term, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// searchDomain returns the registrable domain label of host according to the public suffix
+// list, skipping subdomains and multi-part suffixes: "www.google.co.uk" and "search.yahoo.com"
+// give "google" and "yahoo", while "google.abc.com" gives "abc".
+func searchDomain(host string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(strings.TrimSuffix(host, ".")))
+	if err != nil {
+		return ""
+	}
+	label, _, _ := strings.Cut(domain, ".")
+	return label
+}
@@ -0,0 +1,37 @@
+package browser
+
+import "testing"
+
+func TestParseSearchURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		engine string
+		term   string
+		ok     bool
+	}{
+		{"https://www.google.com/search?q=golang+iterators&oq=golang", "google", "golang iterators", true},
+		{"https://www.google.co.uk/search?q=weather", "google", "weather", true},
+		{"https://www.bing.com/search?q=sqlite%20wal", "bing", "sqlite wal", true},
+		{"https://duckduckgo.com/?q=privacy&ia=web", "duckduckgo", "privacy", true},
+		{"https://html.duckduckgo.com/html/?q=lite", "duckduckgo", "lite", true},
+		{"https://search.yahoo.com/search?p=news", "yahoo", "news", true},
+		{"https://www.youtube.com/results?search_query=go+talks", "youtube", "go talks", true},
+		{"https://www.amazon.de/s?k=keyboard", "amazon", "keyboard", true},
+		{"https://github.com/search?q=cobra&type=repositories", "github", "cobra", true},
+		{"https://www.google.com/maps?q=berlin", "", "", false},
+		{"https://www.google.com/search?q=+", "", "", false},
+		{"https://example.com/search?q=term", "", "", false},
+		{"ftp://www.google.com/search?q=term", "", "", false},
+		{"https://google.abc.com/search?q=term", "", "", false},
+		{"https://bing.xyz.net/search?q=term", "", "", false},
+		{"https://www.google.evil.co.uk/search?q=term", "", "", false},
+		{"https://www.google.com.evil.net/search?q=term", "", "", false},
+		{"https://localhost/search?q=term", "", "", false},
+	}
+	for _, tt := range tests {
+		engine, term, ok := ParseSearchURL(tt.url)
+		if engine != tt.engine || term != tt.term || ok != tt.ok {
+			t.Errorf("ParseSearchURL(%q) = %q, %q, %v; want %q, %q, %v", tt.url, engine, term, ok, tt.engine, tt.term, tt.ok)
+		}
+	}
+}
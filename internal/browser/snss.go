@@ -0,0 +1,132 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"unicode/utf16"
+)
+
+// snssMagic starts every Chromium session file. The header continues with a 32-bit version:
+// 1 and 3 are plain command streams, while 2 and 4 are encrypted and cannot be read.
+var snssMagic = []byte("SNSS")
+
+// snssCommand is one record of a Chromium session file: a command id and its payload.
+type snssCommand struct {
+	id      byte
+	payload []byte
+}
+
+// readSNSSFile reads the command stream of a Chromium session file. A command cut short by an
+// interrupted write ends the stream rather than failing it.
+func readSNSSFile(path string) ([]snssCommand, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 8 || !bytes.Equal(data[:4], snssMagic) {
+		return nil, fmt.Errorf("not an SNSS file")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:8]); version != 1 && version != 3 {
+		return nil, fmt.Errorf("unsupported SNSS version %d", version)
+	}
+
+	var commands []snssCommand
+	for i := 8; i+2 <= len(data); {
+		size := int(binary.LittleEndian.Uint16(data[i:]))
+		i += 2
+		if size == 0 || size > len(data)-i {
+			break
+		}
+		commands = append(commands, snssCommand{id: data[i], payload: data[i+1 : i+size]})
+		i += size
+	}
+	return commands, nil
+}
+
+// snssInt32 reads the 32-bit field at offset of a fixed-layout command payload, or zero when
+// the payload is too short.
+func snssInt32(payload []byte, offset int) int32 {
+	if len(payload) < offset+4 {
+		return 0
+	}
+	return int32(binary.LittleEndian.Uint32(payload[offset:]))
+}
+
+// snssInt64 reads the 64-bit field at offset of a fixed-layout command payload, or zero when
+// the payload is too short.
+func snssInt64(payload []byte, offset int) int64 {
+	if len(payload) < offset+8 {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(payload[offset:]))
+}
+
+// pickleReader reads the fields of a Chromium base::Pickle: a 32-bit payload size followed by
+// fields padded to 4 bytes. The first read past the end sets err and every later read returns
+// a zero value.
+type pickleReader struct {
+	data []byte
+	err  error
+}
+
+func newPickleReader(payload []byte) *pickleReader {
+	if len(payload) < 4 {
+		return &pickleReader{err: fmt.Errorf("pickle: truncated header")}
+	}
+	size := int(binary.LittleEndian.Uint32(payload))
+	if size > len(payload)-4 {
+		size = len(payload) - 4
+	}
+	return &pickleReader{data: payload[4 : 4+size]}
+}
+
+// isPickle reports whether payload is a pickle rather than a fixed-layout struct, by checking
+// that it starts with its own payload size.
+func isPickle(payload []byte) bool {
+	return len(payload) >= 4 && int(binary.LittleEndian.Uint32(payload)) == len(payload)-4
+}
+
+// next consumes n bytes plus their padding.
+func (r *pickleReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	padded := (n + 3) &^ 3
+	if n < 0 || padded > len(r.data) {
+		r.err = fmt.Errorf("pickle: truncated field")
+		return nil
+	}
+	field := r.data[:n]
+	r.data = r.data[padded:]
+	return field
+}
+
+func (r *pickleReader) int32() int32 {
+	if field := r.next(4); field != nil {
+		return int32(binary.LittleEndian.Uint32(field))
+	}
+	return 0
+}
+
+func (r *pickleReader) int64() int64 {
+	if field := r.next(8); field != nil {
+		return int64(binary.LittleEndian.Uint64(field))
+	}
+	return 0
+}
+
+func (r *pickleReader) string() string {
+	return string(r.next(int(r.int32())))
+}
+
+// string16 reads a UTF-16 string, whose length prefix counts code units.
+func (r *pickleReader) string16() string {
+	field := r.next(int(r.int32()) * 2)
+	units := make([]uint16, len(field)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(field[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
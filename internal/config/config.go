@@ -1,6 +1,11 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	HistoryDays int
@@ -12,12 +17,49 @@ type Config struct {
 	Debug       bool // New field for debug logging
 	StartTime   time.Time
 	EndTime     time.Time
+	CustomRange bool         // StartTime was given explicitly instead of derived from HistoryDays
+	ProfileDirs []ProfileDir // Explicit directories read instead of the default OS locations
+	Archives    []string     // .zip/.tar/.tar.gz bundles of profile folders read instead of the default OS locations
+	Root        string       // Mounted image root that default OS locations are rebased onto
+	TargetOS    string       // Operating system of the image under Root; defaults to the running OS
+	Home        string       // Home directory inside Root whose profiles are read
+	AllUsers    bool         // Read every local user's profiles instead of only the current user's
+	Users       []string     // Restrict all-users mode to these usernames
+	Concurrency int          // Number of profile databases copied and started at once
+	Strict      bool         // Fail when any located profile cannot be read instead of skipping it
+	Sources     bool         // Wrap JSON history output in an envelope reporting how each profile was read
+	// RequestTimeout bounds how long an API request may spend reading profiles; zero means no limit.
+	RequestTimeout time.Duration
 }
 
+// ProfileDir is an explicit user data or profile directory and the browser type that wrote it.
+type ProfileDir struct {
+	Browser string
+	Path    string
+}
+
+// ParseProfileDirs parses values of the form "browser=path" into profile directories.
+func ParseProfileDirs(values []string) ([]ProfileDir, error) {
+	var dirs []ProfileDir
+	for _, value := range values {
+		browser, path, ok := strings.Cut(value, "=")
+		browser = strings.TrimSpace(browser)
+		if !ok || browser == "" || path == "" {
+			return nil, fmt.Errorf("invalid profile directory %q (use browser=path)", value)
+		}
+		dirs = append(dirs, ProfileDir{Browser: browser, Path: path})
+	}
+	return dirs, nil
+}
+
+// DefaultConcurrency is the number of profile databases read at once unless configured otherwise.
+const DefaultConcurrency = 4
+
 func NewDefaultConfig() *Config {
 	now := time.Now()
 	return &Config{
 		HistoryDays: 30,
+		Concurrency: DefaultConcurrency,
 		Mode:        "cli",
 		Port:        "8080",
 		Debug:       false, // Debug off by default
@@ -25,3 +67,71 @@ func NewDefaultConfig() *Config {
 		EndTime:     now,
 	}
 }
+
+// timeLayouts are the absolute time formats ParseTime accepts, tried in order. Layouts without
+// a zone are read in local time.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses a time given on the command line relative to now: an RFC3339 time, a local
+// date or date and time such as 2025-04-06 or "2025-04-06 15:04", a duration before now such
+// as 2h, 3d or 2w, or one of "now", "today", "yesterday" and "last <weekday>". Days resolve to
+// local midnight, and d and w count calendar days, keeping the time of day across daylight
+// saving changes.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	keyword := strings.ToLower(value)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch keyword {
+	case "now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	if day, ok := strings.CutPrefix(keyword, "last "); ok {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.TrimSpace(day) == strings.ToLower(weekday.String()) {
+				daysAgo := (int(now.Weekday())-int(weekday)+6)%7 + 1
+				return midnight.AddDate(0, 0, -daysAgo), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q (unknown weekday)", value)
+	}
+
+	if t, err := parseAgo(keyword, now); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339, a date such as 2006-01-02, a duration such as 2h or 3d, today, yesterday or last monday)", value)
+}
+
+// parseAgo returns the time a non-negative duration before now, allowing d and w suffixes for
+// whole calendar days and weeks.
+func parseAgo(value string, now time.Time) (time.Time, error) {
+	for suffix, days := range map[string]int{"d": 1, "w": 7} {
+		if count, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return time.Time{}, fmt.Errorf("invalid duration %q", value)
+			}
+			return now.AddDate(0, 0, -days*n), nil
+		}
+	}
+	ago, err := time.ParseDuration(value)
+	if err != nil || ago < 0 {
+		return time.Time{}, fmt.Errorf("invalid duration %q", value)
+	}
+	return now.Add(-ago), nil
+}
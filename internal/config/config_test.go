@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 	"time"
+	_ "time/tzdata" // America/New_York without a system time zone database.
 )
 
 func TestNewDefaultConfig(t *testing.T) {
@@ -70,3 +71,91 @@ func TestConfigFields(t *testing.T) {
 		t.Errorf("StartTime %v should not be after EndTime %v", cfg.StartTime, cfg.EndTime)
 	}
 }
+
+func TestParseProfileDirs(t *testing.T) {
+	dirs, err := ParseProfileDirs([]string{"chrome=/tmp/User Data", "firefox=C:\\Portable\\Profile=1"})
+	if err != nil {
+		t.Fatalf("ParseProfileDirs() error = %v", err)
+	}
+	expected := []ProfileDir{
+		{Browser: "chrome", Path: "/tmp/User Data"},
+		{Browser: "firefox", Path: "C:\\Portable\\Profile=1"},
+	}
+	if !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("ParseProfileDirs() = %v, want %v", dirs, expected)
+	}
+
+	for _, value := range []string{"/tmp/User Data", "=/tmp", "chrome="} {
+		if _, err := ParseProfileDirs([]string{value}); err == nil {
+			t.Errorf("ParseProfileDirs(%q) expected an error", value)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	// Thursday afternoon
+	now := time.Date(2025, 4, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{"Yesterday", time.Date(2025, 4, 9, 0, 0, 0, 0, time.UTC)},
+		{"last monday", time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)},
+		{"last thursday", time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"last friday", time.Date(2025, 4, 4, 0, 0, 0, 0, time.UTC)},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"3d", now.AddDate(0, 0, -3)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"2025-04-06", time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC)},
+		{"2025-04-06 08:15", time.Date(2025, 4, 6, 8, 15, 0, 0, time.UTC)},
+		{"2025-04-06T08:15:30+02:00", time.Date(2025, 4, 6, 6, 15, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTime(tt.value, now)
+			if err != nil {
+				t.Fatalf("ParseTime(%q) error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "tomorrow", "last someday", "-2h", "-3d", "06/04/2025"} {
+		if _, err := ParseTime(value, now); err == nil {
+			t.Errorf("ParseTime(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestParseTime_DaylightSaving(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load time zone: %v", err)
+	}
+	// Clocks moved forward on 2025-03-09, so the day before was 23 hours long.
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, location)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"1d", time.Date(2025, 3, 9, 12, 0, 0, 0, location)},
+		{"2d", time.Date(2025, 3, 8, 12, 0, 0, 0, location)},
+		{"1w", time.Date(2025, 3, 3, 12, 0, 0, 0, location)},
+		{"48h", time.Date(2025, 3, 8, 11, 0, 0, 0, location)},
+		{"yesterday", time.Date(2025, 3, 9, 0, 0, 0, 0, location)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if err != nil {
+			t.Fatalf("ParseTime(%q) error: %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
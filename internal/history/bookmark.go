@@ -0,0 +1,29 @@
+package history
+
+import (
+	"time"
+)
+
+// BookmarkEntry represents a single browser bookmark.
+type BookmarkEntry struct {
+	URL       string
+	Title     string
+	Folder    string // Slash-separated path of the folders holding the bookmark, e.g. "Bookmarks bar/Go".
+	DateAdded time.Time
+	GUID      string
+	Profile   string
+	Channel   string
+	User      string
+}
+
+type BookmarkOutputEntry struct {
+	DateAdded string `json:"dateAdded"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Folder    string `json:"folder"`
+	GUID      string `json:"guid"`
+	Browser   string `json:"browser"`
+	Profile   string `json:"profile"`
+	Channel   string `json:"channel"`
+	User      string `json:"user"`
+}
@@ -0,0 +1,11 @@
+package history
+
+// ChainOutputEntry is the navigation path that led to a visit, oldest visit first and ending
+// with the visit itself.
+type ChainOutputEntry struct {
+	Browser string        `json:"browser"`
+	Profile string        `json:"profile"`
+	Channel string        `json:"channel"`
+	User    string        `json:"user"`
+	Visits  []OutputEntry `json:"visits"`
+}
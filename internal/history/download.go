@@ -0,0 +1,40 @@
+package history
+
+import (
+	"time"
+)
+
+// DownloadEntry represents a single file download recorded by a browser.
+type DownloadEntry struct {
+	TargetPath    string
+	URLChain      []string // URLs the download was fetched through, ending with the final URL.
+	Referrer      string
+	StartTime     time.Time
+	EndTime       time.Time // Zero while the download is in progress or when the browser does not record it.
+	ReceivedBytes int64
+	TotalBytes    int64
+	DangerType    string
+	State         string
+	MimeType      string
+	Profile       string
+	Channel       string
+	User          string
+}
+
+type DownloadOutputEntry struct {
+	StartTime     string   `json:"startTime"`
+	EndTime       string   `json:"endTime"`
+	TargetPath    string   `json:"targetPath"`
+	URL           string   `json:"url"`
+	URLChain      []string `json:"urlChain"`
+	Referrer      string   `json:"referrer"`
+	ReceivedBytes int64    `json:"receivedBytes"`
+	TotalBytes    int64    `json:"totalBytes"`
+	DangerType    string   `json:"dangerType"`
+	State         string   `json:"state"`
+	MimeType      string   `json:"mimeType"`
+	Browser       string   `json:"browser"`
+	Profile       string   `json:"profile"`
+	Channel       string   `json:"channel"`
+	User          string   `json:"user"`
+}
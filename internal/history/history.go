@@ -8,6 +8,10 @@ type HistoryPathEntry struct {
 	Profile     string
 	ProfileName string
 	Path        string
+	Variant     string // Install variant such as "snap" or "flatpak"; empty for a native install.
+	Channel     string // Release channel such as "stable" or "beta"; empty when the browser has none.
+	User        string // OS username owning the profile; empty for the running user.
+	Extracted   bool   // Path is a private copy, such as one extracted from an archive, read without copying it again.
 }
 
 // HistoryEntry represents a single browser history entry.
@@ -16,18 +20,37 @@ type HistoryEntry struct {
 	Title      string
 	VisitCount int
 	Typed      int
-	VisitType  string
-	Timestamp  time.Time
-	Profile    string
+	VisitType  VisitType
+	// NativeVisitType is the browser's own name for the visit type, e.g. "TRANSITION_LINK".
+	NativeVisitType string
+	Transition      *PageTransition // Chromium's decoded page transition; nil for other browsers.
+	Timestamp       time.Time
+	Duration        time.Duration // Time spent on the page; zero when unknown.
+	// VisitID identifies the visit within its profile database; ReferrerVisitID is the visit that
+	// led to it by link, redirect or opener, or zero when there is none.
+	VisitID         int64
+	ReferrerVisitID int64
+	ReferrerURL     string
+	Profile         string
+	Channel         string
+	User            string
 }
 
 type OutputEntry struct {
-	Timestamp  string `json:"timestamp"`
-	Title      string `json:"title"`
-	URL        string `json:"url"`
-	VisitCount int    `json:"visitCount"`
-	Typed      int    `json:"typed"`
-	VisitType  string `json:"visitType"`
-	Browser    string `json:"browser"`
-	Profile    string `json:"profile"`
+	Timestamp       string          `json:"timestamp"`
+	Title           string          `json:"title"`
+	URL             string          `json:"url"`
+	VisitCount      int             `json:"visitCount"`
+	Typed           int             `json:"typed"`
+	VisitType       VisitType       `json:"visitType"`
+	NativeVisitType string          `json:"nativeVisitType"`
+	Transition      *PageTransition `json:"transition,omitempty"`
+	Duration        float64         `json:"duration"` // Seconds spent on the page; 0 when unknown.
+	VisitID         int64           `json:"visitId"`
+	ReferrerVisitID int64           `json:"referrerVisitId"`
+	ReferrerURL     string          `json:"referrerUrl"`
+	Browser         string          `json:"browser"`
+	Profile         string          `json:"profile"`
+	Channel         string          `json:"channel"`
+	User            string          `json:"user"`
 }
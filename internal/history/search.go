@@ -0,0 +1,35 @@
+package history
+
+import (
+	"time"
+)
+
+// Search term sources reported in SearchTermEntry.Source.
+const (
+	SearchSourceKeyword = "keyword_search_terms" // Chromium's record of omnibox keyword searches.
+	SearchSourceURL     = "url"                  // Parsed from the query string of a visited search results URL.
+)
+
+// SearchTermEntry represents a single search a user ran.
+type SearchTermEntry struct {
+	Engine    string
+	Term      string
+	URL       string
+	Timestamp time.Time
+	Source    string
+	Profile   string
+	Channel   string
+	User      string
+}
+
+type SearchTermOutputEntry struct {
+	Timestamp string `json:"timestamp"`
+	Engine    string `json:"engine"`
+	Term      string `json:"term"`
+	URL       string `json:"url"`
+	Source    string `json:"source"`
+	Browser   string `json:"browser"`
+	Profile   string `json:"profile"`
+	Channel   string `json:"channel"`
+	User      string `json:"user"`
+}
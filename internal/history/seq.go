@@ -0,0 +1,32 @@
+package history
+
+import "iter"
+
+// Collect reads a sequence of entries into a slice, stopping at the first error it yields.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var entries []T
+	for entry, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Values adapts a slice and error result to a sequence: it yields err alone when it is non-nil,
+// otherwise each entry in order.
+func Values[T any](entries []T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for _, entry := range entries {
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"time"
+)
+
+// SessionEntry represents a browser session file: the windows and tabs a profile had open when
+// the file was written, along with recently closed ones.
+type SessionEntry struct {
+	File          string // Base name of the session file, e.g. "recovery.jsonlz4".
+	LastUpdate    time.Time
+	Windows       []SessionWindow
+	ClosedWindows []SessionWindow
+	ClosedTabs    []SessionTab // Recently closed tabs not kept with a window.
+	Profile       string
+	Channel       string
+	User          string
+}
+
+// SessionWindow represents a browser window and the tabs open or recently closed in it.
+type SessionWindow struct {
+	Tabs       []SessionTab
+	ClosedTabs []SessionTab
+	ClosedAt   time.Time // Zero unless the window itself was closed.
+}
+
+// SessionTab represents a tab and its back/forward history.
+type SessionTab struct {
+	URL          string // URL of the page the tab shows.
+	Title        string
+	Pinned       bool
+	LastAccessed time.Time
+	ClosedAt     time.Time // Zero unless the tab was closed.
+	CurrentIndex int       // Index into Navigations of the page the tab shows.
+	Navigations  []SessionNavigation
+}
+
+// SessionNavigation represents one page in a tab's back/forward history.
+type SessionNavigation struct {
+	URL       string
+	Title     string
+	Timestamp time.Time // Zero when the browser does not record it.
+}
+
+// TabsOutputEntry groups the sessions saved in one browser profile.
+type TabsOutputEntry struct {
+	Browser  string               `json:"browser"`
+	Profile  string               `json:"profile"`
+	Channel  string               `json:"channel"`
+	User     string               `json:"user"`
+	Sessions []SessionOutputEntry `json:"sessions"`
+}
+
+type SessionOutputEntry struct {
+	File          string              `json:"file"`
+	LastUpdate    string              `json:"lastUpdate"`
+	Windows       []WindowOutputEntry `json:"windows"`
+	ClosedWindows []WindowOutputEntry `json:"closedWindows"`
+	ClosedTabs    []TabOutputEntry    `json:"closedTabs"`
+}
+
+type WindowOutputEntry struct {
+	ClosedAt   string           `json:"closedAt"`
+	Tabs       []TabOutputEntry `json:"tabs"`
+	ClosedTabs []TabOutputEntry `json:"closedTabs"`
+}
+
+type TabOutputEntry struct {
+	URL          string                  `json:"url"`
+	Title        string                  `json:"title"`
+	Pinned       bool                    `json:"pinned"`
+	LastAccessed string                  `json:"lastAccessed"`
+	ClosedAt     string                  `json:"closedAt"`
+	CurrentIndex int                     `json:"currentIndex"`
+	History      []NavigationOutputEntry `json:"history"`
+}
+
+type NavigationOutputEntry struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Timestamp string `json:"timestamp"`
+}
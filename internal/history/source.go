@@ -0,0 +1,14 @@
+package history
+
+// SourceResult reports how a single browser profile was read: whether it was found, how many
+// entries were read from it and, when reading it failed, why.
+type SourceResult struct {
+	Browser string `json:"browser"`
+	Profile string `json:"profile"`
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+	Path    string `json:"path"`
+	Found   bool   `json:"found"`
+	Entries int    `json:"entries"`
+	Error   string `json:"error,omitempty"`
+}
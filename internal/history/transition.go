@@ -0,0 +1,21 @@
+package history
+
+import (
+	"strings"
+)
+
+// PageTransition is a decoded Chromium page transition: the core type in the low byte and
+// every qualifier flag set in the high bits.
+type PageTransition struct {
+	Raw        int64    `json:"raw"` // The transition as an unsigned 32-bit value.
+	Core       string   `json:"core"`
+	Qualifiers []string `json:"qualifiers"`
+}
+
+// String returns the core type followed by its qualifiers, e.g. "LINK (CHAIN_START, CHAIN_END)".
+func (t PageTransition) String() string {
+	if len(t.Qualifiers) == 0 {
+		return t.Core
+	}
+	return t.Core + " (" + strings.Join(t.Qualifiers, ", ") + ")"
+}
@@ -0,0 +1,21 @@
+package history
+
+// VisitType is a browser-neutral classification of how a visit happened. Each browser maps
+// its native visit or transition type into one of these values and keeps the native string in
+// HistoryEntry.NativeVisitType.
+type VisitType string
+
+const (
+	VisitLink              VisitType = "link"               // Followed a link or otherwise navigated without a more specific cause.
+	VisitTyped             VisitType = "typed"              // Typed into the address bar.
+	VisitBookmark          VisitType = "bookmark"           // Opened from a bookmark.
+	VisitRedirectPermanent VisitType = "redirect_permanent" // Reached through a permanent redirect.
+	VisitRedirectTemporary VisitType = "redirect_temporary" // Reached through a temporary redirect, or any redirect when the browser does not tell them apart.
+	VisitReload            VisitType = "reload"             // Reloaded the page.
+	VisitDownload          VisitType = "download"           // Started a download.
+	VisitEmbed             VisitType = "embed"              // Loaded in a frame or as an embedded resource.
+	VisitFormSubmit        VisitType = "form_submit"        // Submitted a form.
+	VisitGenerated         VisitType = "generated"          // Chosen from an address bar suggestion the browser generated.
+	VisitKeyword           VisitType = "keyword"            // Searched with an address bar keyword.
+	VisitUnknown           VisitType = "unknown"            // The browser recorded no type, or one with no equivalent here.
+)
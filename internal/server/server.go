@@ -1,9 +1,12 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -26,6 +29,11 @@ func Start(cfg *config.Config) error {
 
 	// Register handler safely
 	http.HandleFunc("/history", historyHandler)
+	http.HandleFunc("/bookmarks", bookmarksHandler(service.NewBookmarkService(nil), cfg))
+	http.HandleFunc("/downloads", downloadsHandler(service.NewDownloadService(nil), cfg))
+	http.HandleFunc("/searches", searchesHandler(service.NewSearchTermService(nil), cfg))
+	http.HandleFunc("/chain", chainHandler(service.NewChainService(nil), cfg))
+	http.HandleFunc("/tabs", tabsHandler(service.NewTabService(nil), cfg))
 
 	port := fmt.Sprintf(":%s", cfg.Port)
 	return http.ListenAndServe(port, nil)
@@ -33,62 +41,356 @@ func Start(cfg *config.Config) error {
 
 func historyHandler(srv service.HistoryService, cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+
 		// Parse query parameters
 		query := r.URL.Query()
-		browserParam := query.Get("browsers")
-		daysParam := query.Get("days")
-		startTimeParam := query.Get("start_time")
-		endTimeParam := query.Get("end_time")
 
 		// Clone config to avoid modifying the original
 		localCfg := *cfg
-
-		// Handle browsers
-		var selectedBrowsers []string
-		if browserParam != "" {
-			selectedBrowsers = strings.Split(browserParam, ",")
-		} else if localCfg.Browser != "" {
-			selectedBrowsers = strings.Split(localCfg.Browser, ",")
+		selectedBrowsers, err := applySelection(&localCfg, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := applyTimeRange(&localCfg, query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if sourcesParam := query.Get("sources"); sourcesParam != "" {
+			sources, err := strconv.ParseBool(sourcesParam)
+			if err != nil {
+				http.Error(w, "Invalid 'sources' parameter (use true or false)", http.StatusBadRequest)
+				return
+			}
+			localCfg.Sources = sources
 		}
 
-		// Handle days if provided
-		if daysParam != "" {
-			if days, err := strconv.Atoi(daysParam); err == nil && days > 0 {
-				localCfg.HistoryDays = days
-			} else {
-				http.Error(w, "Invalid 'days' parameter", http.StatusBadRequest)
+		// Stream the entries as they are read instead of collecting them first
+		localCfg.JSONOutput = true
+		localCfg.PrettyPrint = false
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Trailer", streamErrorTrailer)
+		response := &streamingResponse{ResponseWriter: w}
+		var report service.SourceReport
+		entries := srv.StreamHistory(ctx, &localCfg, selectedBrowsers, &report)
+		if localCfg.Sources {
+			err = srv.WriteEnvelope(entries, &report, &localCfg, response)
+		} else {
+			err = srv.WriteResults(entries, &localCfg, response)
+		}
+		if err != nil {
+			if !response.started {
+				http.Error(w, err.Error(), errorStatus(ctx))
 				return
 			}
+			// Part of the array has been sent with a 200 status. The writers have closed the
+			// JSON, adding an "error" field to the envelope, so finish the body and report the
+			// error in a trailer for plain arrays.
+			w.Header().Set(streamErrorTrailer, err.Error())
+		}
+	}
+}
+
+// streamErrorTrailer is the trailer that carries the error that ended a streamed response early.
+const streamErrorTrailer = "X-Stream-Error"
+
+// streamingResponse records whether any of a streamed response body has been written.
+type streamingResponse struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (r *streamingResponse) Write(p []byte) (int, error) {
+	r.started = true
+	return r.ResponseWriter.Write(p)
+}
+
+func bookmarksHandler(srv service.BookmarkService, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+		defer cancel()
 
-		// Handle custom time range if provided
-		if startTimeParam != "" && endTimeParam != "" {
-			startTime, err1 := time.Parse(time.RFC3339, startTimeParam)
-			endTime, err2 := time.Parse(time.RFC3339, endTimeParam)
-			if err1 != nil || err2 != nil || startTime.After(endTime) {
-				http.Error(w, "Invalid 'start_time' or 'end_time' format (use RFC3339)", http.StatusBadRequest)
+		// Clone config to avoid modifying the original
+		localCfg := *cfg
+		selectedBrowsers, err := applySelection(&localCfg, r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := srv.GetBookmarks(ctx, &localCfg, selectedBrowsers)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(ctx))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+	}
+}
+
+func downloadsHandler(srv service.DownloadService, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+
+		query := r.URL.Query()
+
+		// Clone config to avoid modifying the original
+		localCfg := *cfg
+		selectedBrowsers, err := applySelection(&localCfg, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := applyTimeRange(&localCfg, query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := srv.GetDownloads(ctx, &localCfg, selectedBrowsers)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(ctx))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+	}
+}
+
+func searchesHandler(srv service.SearchTermService, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+
+		query := r.URL.Query()
+
+		// Clone config to avoid modifying the original
+		localCfg := *cfg
+		selectedBrowsers, err := applySelection(&localCfg, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := applyTimeRange(&localCfg, query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := srv.GetSearchTerms(ctx, &localCfg, selectedBrowsers)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(ctx))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+	}
+}
+
+func chainHandler(srv service.ChainService, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+
+		query := r.URL.Query()
+
+		target := service.ChainTarget{URL: query.Get("url"), Profile: query.Get("profile")}
+		if visitIDParam := query.Get("visit_id"); visitIDParam != "" {
+			visitID, err := strconv.ParseInt(visitIDParam, 10, 64)
+			if err != nil || visitID <= 0 {
+				http.Error(w, "Invalid 'visit_id' parameter", http.StatusBadRequest)
 				return
 			}
-			localCfg.StartTime = startTime
-			localCfg.EndTime = endTime
-		} else if startTimeParam == "" && endTimeParam == "" {
-			// Use default time range based on days if no custom range is specified
-			localCfg.EndTime = time.Now()
-			localCfg.StartTime = localCfg.EndTime.AddDate(0, 0, -localCfg.HistoryDays)
-		} else {
-			http.Error(w, "Both 'start_time' and 'end_time' must be provided together", http.StatusBadRequest)
+			target.VisitID = visitID
+		}
+		if target.URL == "" && target.VisitID == 0 {
+			http.Error(w, "Either 'url' or 'visit_id' must be provided", http.StatusBadRequest)
+			return
+		}
+
+		// Clone config to avoid modifying the original
+		localCfg := *cfg
+		selectedBrowsers, err := applySelection(&localCfg, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if target.URL == "" && (len(selectedBrowsers) != 1 || target.Profile == "") {
+			http.Error(w, "'visit_id' needs a single browser in 'browsers' and a 'profile', as visit ids are only unique within one profile", http.StatusBadRequest)
+			return
+		}
+		if err := applyTimeRange(&localCfg, query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := srv.GetChains(ctx, &localCfg, selectedBrowsers, target)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(ctx))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(entries); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		}
+	}
+}
+
+func tabsHandler(srv service.TabService, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel, err := requestContext(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		defer cancel()
 
-		entries, err := srv.GetHistory(&localCfg, selectedBrowsers)
+		// Clone config to avoid modifying the original
+		localCfg := *cfg
+		selectedBrowsers, err := applySelection(&localCfg, r.URL.Query())
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		entries, err := srv.GetTabs(ctx, &localCfg, selectedBrowsers)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(ctx))
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(entries); err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		}
 	}
 }
+
+// requestContext returns the context a request's profiles are read under. It is cancelled when
+// the client disconnects and expires after the request's timeout parameter or cfg.RequestTimeout,
+// whichever is shorter.
+func requestContext(r *http.Request, cfg *config.Config) (context.Context, context.CancelFunc, error) {
+	timeout := cfg.RequestTimeout
+	if timeoutParam := r.URL.Query().Get("timeout"); timeoutParam != "" {
+		requested, err := time.ParseDuration(timeoutParam)
+		if err != nil || requested <= 0 {
+			return nil, nil, fmt.Errorf("Invalid 'timeout' parameter (use a duration such as 30s)")
+		}
+		if timeout == 0 || requested < timeout {
+			timeout = requested
+		}
+	}
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+// errorStatus returns the status for a failed request: 504 once its deadline has passed,
+// otherwise 400.
+func errorStatus(ctx context.Context) int {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadRequest
+}
+
+// applySelection applies the browsers, profile_dir and strict query parameters shared by every
+// route to cfg and returns the selected browsers.
+func applySelection(cfg *config.Config, query url.Values) ([]string, error) {
+	// Handle browsers
+	var selectedBrowsers []string
+	if browserParam := query.Get("browsers"); browserParam != "" {
+		selectedBrowsers = strings.Split(browserParam, ",")
+	} else if cfg.Browser != "" {
+		selectedBrowsers = strings.Split(cfg.Browser, ",")
+	}
+
+	// Handle explicit profile directories if provided
+	if profileDirParams := query["profile_dir"]; len(profileDirParams) > 0 {
+		profileDirs, err := config.ParseProfileDirs(profileDirParams)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid 'profile_dir' parameter: %v", err)
+		}
+		cfg.ProfileDirs = profileDirs
+	}
+
+	// Fail on any profile that cannot be read when strict mode is requested
+	if strictParam := query.Get("strict"); strictParam != "" {
+		strict, err := strconv.ParseBool(strictParam)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid 'strict' parameter (use true or false)")
+		}
+		cfg.Strict = strict
+	}
+	return selectedBrowsers, nil
+}
+
+// applyTimeRange applies the days, start_time and end_time query parameters to cfg.
+func applyTimeRange(cfg *config.Config, query url.Values) error {
+	daysParam := query.Get("days")
+	startTimeParam := query.Get("start_time")
+	endTimeParam := query.Get("end_time")
+
+	// Handle days if provided
+	if daysParam != "" {
+		days, err := strconv.Atoi(daysParam)
+		if err != nil || days <= 0 {
+			return fmt.Errorf("Invalid 'days' parameter")
+		}
+		cfg.HistoryDays = days
+	}
+
+	// Handle custom time range if provided
+	if startTimeParam != "" && endTimeParam != "" {
+		startTime, err1 := time.Parse(time.RFC3339, startTimeParam)
+		endTime, err2 := time.Parse(time.RFC3339, endTimeParam)
+		if err1 != nil || err2 != nil || startTime.After(endTime) {
+			return fmt.Errorf("Invalid 'start_time' or 'end_time' format (use RFC3339)")
+		}
+		cfg.StartTime = startTime
+		cfg.EndTime = endTime
+		cfg.CustomRange = true
+	} else if startTimeParam == "" && endTimeParam == "" {
+		// Use default time range based on days if no custom range is specified
+		cfg.EndTime = time.Now()
+		cfg.StartTime = cfg.EndTime.AddDate(0, 0, -cfg.HistoryDays)
+		cfg.CustomRange = false
+	} else {
+		return fmt.Errorf("Both 'start_time' and 'end_time' must be provided together")
+	}
+	return nil
+}
@@ -1,15 +1,18 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/lotekdan/go-browser-history/internal/browser"
 	"github.com/lotekdan/go-browser-history/internal/config"
 	"github.com/lotekdan/go-browser-history/internal/history"
 	"github.com/lotekdan/go-browser-history/internal/service"
@@ -17,16 +20,40 @@ import (
 
 // mockHistoryService implements service.HistoryService
 type mockHistoryService struct {
-	getHistoryFunc func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error)
+	getHistoryFunc    func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error)
+	streamHistoryFunc func(cfg *config.Config, selectedBrowsers []string) iter.Seq2[history.OutputEntry, error]
+	sources           []history.SourceResult // Reported by StreamHistory
 }
 
-func (m *mockHistoryService) GetHistory(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+func (m *mockHistoryService) GetHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
 	if m.getHistoryFunc != nil {
 		return m.getHistoryFunc(cfg, selectedBrowsers)
 	}
 	return nil, nil
 }
 
+func (m *mockHistoryService) StreamHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string, report *service.SourceReport) iter.Seq2[history.OutputEntry, error] {
+	if report != nil {
+		report.Sources = m.sources
+	}
+	if m.streamHistoryFunc != nil {
+		return m.streamHistoryFunc(cfg, selectedBrowsers)
+	}
+	return history.Values(m.GetHistory(ctx, cfg, selectedBrowsers))
+}
+
+func (m *mockHistoryService) WriteResults(entries iter.Seq2[history.OutputEntry, error], cfg *config.Config, writer io.Writer) error {
+	return service.NewHistoryService(map[string]browser.Browser{}).WriteResults(entries, cfg, writer)
+}
+
+func (m *mockHistoryService) WriteEnvelope(entries iter.Seq2[history.OutputEntry, error], report *service.SourceReport, cfg *config.Config, writer io.Writer) error {
+	return service.NewHistoryService(map[string]browser.Browser{}).WriteEnvelope(entries, report, cfg, writer)
+}
+
+func (m *mockHistoryService) OutputSources(report *service.SourceReport, writer io.Writer) {
+	service.NewHistoryService(map[string]browser.Browser{}).OutputSources(report, writer)
+}
+
 func (m *mockHistoryService) OutputResults(entries []history.OutputEntry, cfg *config.Config, writer io.Writer) {
 	if cfg.JSONOutput {
 		jsonData, err := json.Marshal(entries)
@@ -154,6 +181,136 @@ func TestHistoryHandler_BrowsersParam(t *testing.T) {
 	}
 }
 
+func TestHistoryHandler_ProfileDirParam(t *testing.T) {
+	var gotDirs []config.ProfileDir
+	srv := &mockHistoryService{
+		getHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+			gotDirs = cfg.ProfileDirs
+			return nil, nil
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	req, _ := http.NewRequest("GET", "/history?profile_dir=chrome%3D%2Fcopies%2FUser+Data&profile_dir=firefox%3D%2Fcopies%2Fff", nil)
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	expected := []config.ProfileDir{{Browser: "chrome", Path: "/copies/User Data"}, {Browser: "firefox", Path: "/copies/ff"}}
+	if len(gotDirs) != 2 || gotDirs[0] != expected[0] || gotDirs[1] != expected[1] {
+		t.Errorf("ProfileDirs = %v, want %v", gotDirs, expected)
+	}
+	if len(cfg.ProfileDirs) != 0 {
+		t.Errorf("handler modified the shared config: %v", cfg.ProfileDirs)
+	}
+}
+
+func TestHistoryHandler_SourcesParam(t *testing.T) {
+	srv := &mockHistoryService{
+		getHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+			return []history.OutputEntry{{Title: "Test", Browser: "chrome"}}, nil
+		},
+		sources: []history.SourceResult{
+			{Browser: "chrome", Profile: "Default", Found: true, Entries: 1},
+			{Browser: "firefox", Profile: "default-release", Found: true, Error: "database is locked"},
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?sources=true", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	var envelope struct {
+		Entries []history.OutputEntry  `json:"entries"`
+		Sources []history.SourceResult `json:"sources"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Failed to decode envelope: %v", err)
+	}
+	if len(envelope.Entries) != 1 || envelope.Entries[0].Title != "Test" {
+		t.Errorf("entries = %v, want the single Test entry", envelope.Entries)
+	}
+	if len(envelope.Sources) != 2 || envelope.Sources[1].Error != "database is locked" {
+		t.Errorf("sources = %v, want %v", envelope.Sources, srv.sources)
+	}
+
+	rr = httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?sources=maybe", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHistoryHandler_StrictParam(t *testing.T) {
+	var gotStrict bool
+	srv := &mockHistoryService{
+		getHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+			gotStrict = cfg.Strict
+			return nil, nil
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?strict=true", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !gotStrict {
+		t.Error("Expected strict=true to enable strict mode")
+	}
+
+	rr = httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?strict=yes", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHistoryHandler_TimeRangeParam(t *testing.T) {
+	var got config.Config
+	srv := &mockHistoryService{
+		getHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+			got = *cfg
+			return nil, nil
+		},
+	}
+	// A custom range from the command line must not leak into requests without one.
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now(), CustomRange: true}
+
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?start_time=2025-01-01T00:00:00Z&end_time=2025-02-01T00:00:00Z", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !got.CustomRange || !got.StartTime.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartTime = %v (custom %v), want the explicit start_time", got.StartTime, got.CustomRange)
+	}
+
+	rr = httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?days=7", nil))
+	if got.CustomRange {
+		t.Error("Expected a days-only request to derive its start from days")
+	}
+}
+
+func TestHistoryHandler_ProfileDirParamInvalid(t *testing.T) {
+	srv := &mockHistoryService{}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	req, _ := http.NewRequest("GET", "/history?profile_dir=%2Fno%2Fbrowser", nil)
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHistoryHandler_DaysParamInvalid(t *testing.T) {
 	srv := &mockHistoryService{}
 	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
@@ -225,3 +382,373 @@ func TestHistoryHandler_GetHistoryError(t *testing.T) {
 		t.Errorf("response body = %q, want %q", body, "history fetch failed\n")
 	}
 }
+
+func TestHistoryHandler_StreamError(t *testing.T) {
+	srv := &mockHistoryService{
+		streamHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) iter.Seq2[history.OutputEntry, error] {
+			return func(yield func(history.OutputEntry, error) bool) {
+				if yield(history.OutputEntry{Title: "Test", Browser: "chrome"}, nil) {
+					yield(history.OutputEntry{}, errors.New("database went away"))
+				}
+			}
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	server := httptest.NewServer(historyHandler(srv, cfg))
+	defer server.Close()
+
+	// The entries already sent are completed as valid JSON and the error is reported in a
+	// trailer, or in the envelope's "error" field with sources=true.
+	t.Run("Array", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/history")
+		if err != nil {
+			t.Fatalf("Failed to get history: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var entries []history.OutputEntry
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			t.Fatalf("Expected a complete JSON array, got %v", err)
+		}
+		if len(entries) != 1 || entries[0].Title != "Test" {
+			t.Errorf("Expected the entry read before the error, got %v", entries)
+		}
+		io.Copy(io.Discard, resp.Body)
+		if got := resp.Trailer.Get("X-Stream-Error"); got != "database went away" {
+			t.Errorf("Expected the error in the X-Stream-Error trailer, got %q", got)
+		}
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/history?sources=true")
+		if err != nil {
+			t.Fatalf("Failed to get history: %v", err)
+		}
+		defer resp.Body.Close()
+		var envelope struct {
+			Entries []history.OutputEntry `json:"entries"`
+			Error   string                `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("Expected a complete JSON envelope, got %v", err)
+		}
+		if len(envelope.Entries) != 1 {
+			t.Errorf("Expected 1 entry, got %d", len(envelope.Entries))
+		}
+		if envelope.Error != "database went away" {
+			t.Errorf("Expected the envelope error %q, got %q", "database went away", envelope.Error)
+		}
+	})
+}
+
+// mockBookmarkService implements service.BookmarkService
+type mockBookmarkService struct {
+	getBookmarksFunc func(cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error)
+}
+
+func (m *mockBookmarkService) GetBookmarks(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error) {
+	if m.getBookmarksFunc != nil {
+		return m.getBookmarksFunc(cfg, selectedBrowsers)
+	}
+	return nil, nil
+}
+
+func (m *mockBookmarkService) OutputBookmarks(entries []history.BookmarkOutputEntry, cfg *config.Config, writer io.Writer) {
+}
+
+func TestBookmarksHandler_Success(t *testing.T) {
+	var gotBrowsers []string
+	var gotDirs []config.ProfileDir
+	srv := &mockBookmarkService{
+		getBookmarksFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error) {
+			gotBrowsers = selectedBrowsers
+			gotDirs = cfg.ProfileDirs
+			return []history.BookmarkOutputEntry{{URL: "https://go.dev/", Folder: "Bookmarks bar", Browser: "chrome"}}, nil
+		},
+	}
+	cfg := &config.Config{}
+
+	req, _ := http.NewRequest("GET", "/bookmarks?browsers=chrome,firefox&profile_dir=chrome%3D%2Fcopies%2Fchrome", nil)
+	rr := httptest.NewRecorder()
+	bookmarksHandler(srv, cfg).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if len(gotBrowsers) != 2 || gotBrowsers[0] != "chrome" || gotBrowsers[1] != "firefox" {
+		t.Errorf("selectedBrowsers = %v, want [chrome firefox]", gotBrowsers)
+	}
+	if len(gotDirs) != 1 || gotDirs[0] != (config.ProfileDir{Browser: "chrome", Path: "/copies/chrome"}) {
+		t.Errorf("ProfileDirs = %v", gotDirs)
+	}
+	var entries []history.BookmarkOutputEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil || len(entries) != 1 || entries[0].Folder != "Bookmarks bar" {
+		t.Errorf("Unexpected response %v (%v)", entries, err)
+	}
+}
+
+func TestBookmarksHandler_Error(t *testing.T) {
+	srv := &mockBookmarkService{
+		getBookmarksFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error) {
+			return nil, errors.New("no valid browsers specified")
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/bookmarks?browsers=unknown", nil)
+	rr := httptest.NewRecorder()
+	bookmarksHandler(srv, &config.Config{}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+// mockDownloadService implements service.DownloadService
+type mockDownloadService struct {
+	getDownloadsFunc func(cfg *config.Config, selectedBrowsers []string) ([]history.DownloadOutputEntry, error)
+}
+
+func (m *mockDownloadService) GetDownloads(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.DownloadOutputEntry, error) {
+	if m.getDownloadsFunc != nil {
+		return m.getDownloadsFunc(cfg, selectedBrowsers)
+	}
+	return nil, nil
+}
+
+func (m *mockDownloadService) OutputDownloads(entries []history.DownloadOutputEntry, cfg *config.Config, writer io.Writer) {
+}
+
+func TestDownloadsHandler_Success(t *testing.T) {
+	var gotCfg config.Config
+	srv := &mockDownloadService{
+		getDownloadsFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.DownloadOutputEntry, error) {
+			gotCfg = *cfg
+			return []history.DownloadOutputEntry{{TargetPath: "/tmp/go.tar.gz", State: "COMPLETE", Browser: "chrome"}}, nil
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30}
+
+	req, _ := http.NewRequest("GET", "/downloads?browsers=chrome&days=3", nil)
+	rr := httptest.NewRecorder()
+	downloadsHandler(srv, cfg).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if gotCfg.HistoryDays != 3 {
+		t.Errorf("HistoryDays = %d, want 3", gotCfg.HistoryDays)
+	}
+	var entries []history.DownloadOutputEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil || len(entries) != 1 || entries[0].State != "COMPLETE" {
+		t.Errorf("Unexpected response %v (%v)", entries, err)
+	}
+}
+
+func TestDownloadsHandler_PartialTimeRange(t *testing.T) {
+	srv := &mockDownloadService{}
+	req, _ := http.NewRequest("GET", "/downloads?start_time=2025-01-01T00:00:00Z", nil)
+	rr := httptest.NewRecorder()
+	downloadsHandler(srv, &config.Config{HistoryDays: 30}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+// mockSearchTermService implements service.SearchTermService
+type mockSearchTermService struct {
+	getSearchTermsFunc func(cfg *config.Config, selectedBrowsers []string) ([]history.SearchTermOutputEntry, error)
+}
+
+func (m *mockSearchTermService) GetSearchTerms(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.SearchTermOutputEntry, error) {
+	if m.getSearchTermsFunc != nil {
+		return m.getSearchTermsFunc(cfg, selectedBrowsers)
+	}
+	return nil, nil
+}
+
+func (m *mockSearchTermService) OutputSearchTerms(entries []history.SearchTermOutputEntry, cfg *config.Config, writer io.Writer) {
+}
+
+func TestSearchesHandler_Success(t *testing.T) {
+	var gotBrowsers []string
+	srv := &mockSearchTermService{
+		getSearchTermsFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.SearchTermOutputEntry, error) {
+			gotBrowsers = selectedBrowsers
+			return []history.SearchTermOutputEntry{{Engine: "google", Term: "go iterators", Browser: "chrome"}}, nil
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/searches?browsers=chrome,firefox", nil)
+	rr := httptest.NewRecorder()
+	searchesHandler(srv, &config.Config{HistoryDays: 30}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if len(gotBrowsers) != 2 || gotBrowsers[0] != "chrome" || gotBrowsers[1] != "firefox" {
+		t.Errorf("selectedBrowsers = %v, want [chrome firefox]", gotBrowsers)
+	}
+	var entries []history.SearchTermOutputEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil || len(entries) != 1 || entries[0].Term != "go iterators" {
+		t.Errorf("Unexpected response %v (%v)", entries, err)
+	}
+}
+
+// mockChainService implements service.ChainService
+type mockChainService struct {
+	getChainsFunc func(cfg *config.Config, selectedBrowsers []string, target service.ChainTarget) ([]history.ChainOutputEntry, error)
+}
+
+func (m *mockChainService) GetChains(ctx context.Context, cfg *config.Config, selectedBrowsers []string, target service.ChainTarget) ([]history.ChainOutputEntry, error) {
+	if m.getChainsFunc != nil {
+		return m.getChainsFunc(cfg, selectedBrowsers, target)
+	}
+	return nil, nil
+}
+
+func (m *mockChainService) OutputChains(entries []history.ChainOutputEntry, cfg *config.Config, writer io.Writer) {
+}
+
+func TestChainHandler_Success(t *testing.T) {
+	var gotTarget service.ChainTarget
+	srv := &mockChainService{
+		getChainsFunc: func(cfg *config.Config, selectedBrowsers []string, target service.ChainTarget) ([]history.ChainOutputEntry, error) {
+			gotTarget = target
+			return []history.ChainOutputEntry{{Browser: "chrome", Visits: []history.OutputEntry{{URL: "https://go.dev/", VisitID: 42}}}}, nil
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/chain?visit_id=42&browsers=chrome&profile=Default", nil)
+	rr := httptest.NewRecorder()
+	chainHandler(srv, &config.Config{HistoryDays: 30}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d", rr.Code, http.StatusOK)
+	}
+	if gotTarget.VisitID != 42 || gotTarget.URL != "" || gotTarget.Profile != "Default" {
+		t.Errorf("target = %+v, want visit 42 of Default", gotTarget)
+	}
+	var entries []history.ChainOutputEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil || len(entries) != 1 || entries[0].Visits[0].VisitID != 42 {
+		t.Errorf("Unexpected response %v (%v)", entries, err)
+	}
+}
+
+func TestChainHandler_InvalidTarget(t *testing.T) {
+	for _, query := range []string{"", "?visit_id=abc", "?visit_id=-1", "?visit_id=42&profile=Default",
+		"?visit_id=42&browsers=chrome", "?visit_id=42&browsers=chrome,firefox&profile=Default"} {
+		req, _ := http.NewRequest("GET", "/chain"+query, nil)
+		rr := httptest.NewRecorder()
+		chainHandler(&mockChainService{}, &config.Config{HistoryDays: 30}).ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%q: handler returned status %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+// mockTabService implements service.TabService
+type mockTabService struct {
+	getTabsFunc func(cfg *config.Config, selectedBrowsers []string) ([]history.TabsOutputEntry, error)
+}
+
+func (m *mockTabService) GetTabs(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.TabsOutputEntry, error) {
+	if m.getTabsFunc != nil {
+		return m.getTabsFunc(cfg, selectedBrowsers)
+	}
+	return nil, nil
+}
+
+func (m *mockTabService) OutputTabs(entries []history.TabsOutputEntry, cfg *config.Config, writer io.Writer) {
+}
+
+func TestTabsHandler(t *testing.T) {
+	srv := &mockTabService{
+		getTabsFunc: func(cfg *config.Config, selectedBrowsers []string) ([]history.TabsOutputEntry, error) {
+			if len(selectedBrowsers) != 1 || selectedBrowsers[0] != "firefox" {
+				return nil, fmt.Errorf("unexpected browsers %v", selectedBrowsers)
+			}
+			return []history.TabsOutputEntry{{Browser: "firefox", Sessions: []history.SessionOutputEntry{{File: "recovery.jsonlz4"}}}}, nil
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/tabs?browsers=firefox", nil)
+	rr := httptest.NewRecorder()
+	tabsHandler(srv, &config.Config{}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var entries []history.TabsOutputEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil || len(entries) != 1 || entries[0].Sessions[0].File != "recovery.jsonlz4" {
+		t.Errorf("Unexpected response %v (%v)", entries, err)
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   time.Duration
+		query   string
+		want    time.Duration // Zero for no deadline.
+		wantErr bool
+	}{
+		{name: "NoLimit"},
+		{name: "ServerLimit", limit: time.Minute, want: time.Minute},
+		{name: "ShorterRequest", limit: time.Minute, query: "?timeout=10s", want: 10 * time.Second},
+		{name: "CappedRequest", limit: time.Minute, query: "?timeout=5m", want: time.Minute},
+		{name: "RequestOnly", query: "?timeout=2s", want: 2 * time.Second},
+		{name: "Invalid", query: "?timeout=soon", wantErr: true},
+		{name: "NotPositive", query: "?timeout=0s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/history"+tt.query, nil)
+			ctx, cancel, err := requestContext(req, &config.Config{RequestTimeout: tt.limit})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error for an invalid timeout")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestContext failed: %v", err)
+			}
+			defer cancel()
+			deadline, ok := ctx.Deadline()
+			if ok != (tt.want != 0) {
+				t.Fatalf("Deadline set = %v, want %v", ok, tt.want != 0)
+			}
+			if remaining := time.Until(deadline); ok && (remaining > tt.want || remaining < tt.want-time.Second) {
+				t.Errorf("Deadline in %v, want about %v", remaining, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryHandler_Timeout(t *testing.T) {
+	srv := &mockHistoryService{
+		streamHistoryFunc: func(cfg *config.Config, selectedBrowsers []string) iter.Seq2[history.OutputEntry, error] {
+			return func(yield func(history.OutputEntry, error) bool) {
+				time.Sleep(20 * time.Millisecond)
+				yield(history.OutputEntry{}, errors.New("interrupted"))
+			}
+		},
+	}
+	cfg := &config.Config{HistoryDays: 30, EndTime: time.Now()}
+
+	rr := httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?timeout=1ms", nil))
+	if rr.Code != http.StatusGatewayTimeout {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusGatewayTimeout)
+	}
+
+	rr = httptest.NewRecorder()
+	historyHandler(srv, cfg).ServeHTTP(rr, httptest.NewRequest("GET", "/history?timeout=soon", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
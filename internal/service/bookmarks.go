@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/utils"
+)
+
+// BookmarkService retrieves bookmarks from the same profiles HistoryService reads history from.
+type BookmarkService interface {
+	GetBookmarks(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error)
+	OutputBookmarks(entries []history.BookmarkOutputEntry, cfg *config.Config, writer io.Writer)
+}
+
+// Ensure historyService implements the interface
+var _ BookmarkService = (*historyService)(nil)
+
+// NewBookmarkService creates a BookmarkService over the given browsers, or every registered browser when nil.
+func NewBookmarkService(browserMap map[string]browser.Browser) BookmarkService {
+	return NewHistoryService(browserMap).(*historyService)
+}
+
+// GetBookmarks reads the bookmarks of the selected browsers' profiles, honouring the same
+// profile directory, archive, root and user selection as GetHistory.
+func (s *historyService) GetBookmarks(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.BookmarkOutputEntry, error) {
+	sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(bookmarkBrowser browser.BookmarkBrowser, path history.HistoryPathEntry) ([]history.BookmarkEntry, error) {
+		return utils.ProfileBookmarks(ctx, bookmarkBrowser, path, shouldLog(cfg))
+	}
+	return readProfiles(ctx, cfg, sources, explicit, "bookmarks", read, utils.ToBookmarkOutputEntries)
+}
+
+// OutputBookmarks writes bookmarks as JSON or as one text line per bookmark.
+func (s *historyService) OutputBookmarks(entries []history.BookmarkOutputEntry, cfg *config.Config, writer io.Writer) {
+	if cfg.JSONOutput {
+		writeJSON(entries, cfg, writer)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(writer, "No bookmarks found.")
+		return
+	}
+
+	for _, entry := range entries {
+		title := entry.Title
+		if title == "" {
+			title = "(no title)"
+		}
+		fmt.Fprintf(writer, "%-30s %-50s (%s) [%s] [%s] [%s]",
+			entry.DateAdded,
+			title,
+			entry.URL,
+			entry.Folder,
+			entry.Browser,
+			entry.Profile)
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		fmt.Fprintln(writer)
+	}
+}
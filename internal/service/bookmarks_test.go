@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookmarkService_GetBookmarks(t *testing.T) {
+	profileDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(profileDir, "History"), []byte("mock data"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(profileDir, "Preferences"), []byte(`{"profile": {"name": "Person 1"}}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(profileDir, "Bookmarks"), []byte(`{"roots": {"bookmark_bar": {
+		"type": "folder", "name": "Bookmarks bar", "children": [
+			{"type": "url", "name": "Go", "url": "https://go.dev/", "guid": "g1", "date_added": "13350000000000000"}
+		]}}}`), 0644))
+
+	service := NewBookmarkService(map[string]browser.Browser{
+		"chrome":  browser.NewChromeBrowser(),
+		"firefox": new(MockBrowser),
+		"edge":    &mockDirectoryBrowser{dbPath: filepath.Join(profileDir, "History")},
+	})
+
+	t.Run("ProfileDir", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "chrome", Path: profileDir}}}
+		entries, err := service.GetBookmarks(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []history.BookmarkOutputEntry{{
+			DateAdded: browser.ChromeTimeToTime(13350000000000000).Format(time.RFC3339),
+			Title:     "Go",
+			URL:       "https://go.dev/",
+			Folder:    "Bookmarks bar",
+			GUID:      "g1",
+			Browser:   "chrome",
+			Profile:   "Person 1",
+		}}, entries)
+	})
+
+	t.Run("UnsupportedBrowser", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "edge", Path: profileDir}}}
+		_, err := service.GetBookmarks(context.Background(), cfg, nil)
+		assert.ErrorContains(t, err, `browser "edge" does not support bookmarks`)
+	})
+
+	t.Run("Archives", func(t *testing.T) {
+		archivePath := filepath.Join(t.TempDir(), "profiles.zip")
+		writeZip(t, archivePath, map[string]string{
+			"bob/.config/google-chrome/Default/History":   "mock data",
+			"bob/.config/google-chrome/Default/Bookmarks": `{"roots": {"other": {"type": "folder", "name": "Other bookmarks", "children": [{"type": "url", "name": "Go", "url": "https://go.dev/"}]}}}`,
+		})
+		cfg := &config.Config{Archives: []string{archivePath}}
+		entries, err := service.GetBookmarks(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "https://go.dev/", entries[0].URL)
+			assert.Equal(t, "chrome", entries[0].Browser)
+			assert.Equal(t, "Default", entries[0].Profile)
+		}
+	})
+
+	t.Run("NoValidBrowsers", func(t *testing.T) {
+		_, err := service.GetBookmarks(context.Background(), &config.Config{}, []string{"unknown"})
+		assert.ErrorContains(t, err, "no valid browsers specified")
+	})
+}
+
+// mockProfilesBrowser is a Chrome browser whose default locations hold the given profiles.
+type mockProfilesBrowser struct {
+	browser.ChromeBrowser
+	paths []history.HistoryPathEntry
+}
+
+func (m *mockProfilesBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return m.paths, nil
+}
+
+func TestBookmarkService_SkipsUnreadableProfiles(t *testing.T) {
+	var paths []history.HistoryPathEntry
+	for name, bookmarks := range map[string]string{
+		"Broken":  "not json",
+		"Default": `{"roots": {"other": {"type": "folder", "name": "Other bookmarks", "children": [{"type": "url", "name": "Go", "url": "https://go.dev/"}]}}}`,
+	} {
+		profileDir := filepath.Join(t.TempDir(), name)
+		assert.NoError(t, os.MkdirAll(profileDir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(profileDir, "Bookmarks"), []byte(bookmarks), 0644))
+		paths = append(paths, history.HistoryPathEntry{Path: filepath.Join(profileDir, "History"), ProfileName: name})
+	}
+	// The unreadable profile comes first, so the readable one is only reached if it is skipped.
+	if paths[0].ProfileName != "Broken" {
+		paths[0], paths[1] = paths[1], paths[0]
+	}
+	service := NewBookmarkService(map[string]browser.Browser{"chrome": &mockProfilesBrowser{paths: paths}})
+
+	t.Run("Default", func(t *testing.T) {
+		entries, err := service.GetBookmarks(context.Background(), &config.Config{}, []string{"chrome"})
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "Default", entries[0].Profile)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		_, err := service.GetBookmarks(context.Background(), &config.Config{Strict: true}, []string{"chrome"})
+		assert.ErrorContains(t, err, "failed to read chrome bookmarks")
+	})
+}
+
+func TestBookmarkService_OutputBookmarks(t *testing.T) {
+	service := NewBookmarkService(map[string]browser.Browser{})
+	entries := []history.BookmarkOutputEntry{{
+		DateAdded: "2024-01-01T00:00:00Z",
+		Title:     "Go",
+		URL:       "https://go.dev/",
+		Folder:    "Bookmarks bar",
+		Browser:   "chrome",
+		Profile:   "Default",
+		User:      "alice",
+	}}
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputBookmarks(entries, &config.Config{}, &buf)
+		assert.Equal(t, "2024-01-01T00:00:00Z           Go                                                 (https://go.dev/) [Bookmarks bar] [chrome] [Default] [alice]\n", buf.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputBookmarks(entries, &config.Config{JSONOutput: true}, &buf)
+		assert.Contains(t, buf.String(), `"folder":"Bookmarks bar"`)
+	})
+
+	t.Run("NoEntries", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputBookmarks(nil, &config.Config{}, &buf)
+		assert.Equal(t, "No bookmarks found.\n", buf.String())
+	})
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/utils"
+)
+
+// ChainTarget selects the visits whose navigation chains are reconstructed: every visit to URL,
+// or the visit with VisitID when URL is empty. Visit ids are only unique within one database,
+// so a VisitID also needs Profile, the directory or display name of the profile it belongs to.
+type ChainTarget struct {
+	URL     string
+	VisitID int64
+	Profile string
+}
+
+// ChainService reconstructs the navigation paths that led to a URL or visit.
+type ChainService interface {
+	GetChains(ctx context.Context, cfg *config.Config, selectedBrowsers []string, target ChainTarget) ([]history.ChainOutputEntry, error)
+	OutputChains(entries []history.ChainOutputEntry, cfg *config.Config, writer io.Writer)
+}
+
+// Ensure historyService implements the interface
+var _ ChainService = (*historyService)(nil)
+
+// NewChainService creates a ChainService over the given browsers, or every registered browser when nil.
+func NewChainService(browserMap map[string]browser.Browser) ChainService {
+	return NewHistoryService(browserMap).(*historyService)
+}
+
+// GetChains reconstructs, for every visit matching target within cfg's time range, the chain
+// of referring visits and redirects that led to it. Referrers older than the time range end
+// the chain with their URL only.
+func (s *historyService) GetChains(ctx context.Context, cfg *config.Config, selectedBrowsers []string, target ChainTarget) ([]history.ChainOutputEntry, error) {
+	if target.URL == "" && target.VisitID == 0 {
+		return nil, fmt.Errorf("a URL or visit id is required")
+	}
+	if target.URL == "" && (len(selectedBrowsers) != 1 || target.Profile == "") {
+		return nil, fmt.Errorf("a visit id needs a single browser and a profile, as visit ids are only unique within one profile")
+	}
+	matches := func(entry history.HistoryEntry) bool {
+		if target.URL != "" {
+			return entry.URL == target.URL
+		}
+		return entry.VisitID == target.VisitID
+	}
+
+	resolveTimeRange(cfg)
+	sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+	if target.URL == "" {
+		source, err := visitSource(sources, selectedBrowsers[0], target.Profile)
+		if err != nil {
+			return nil, err
+		}
+		sources, explicit = []profileSource{source}, true
+	}
+
+	var entries []history.ChainOutputEntry
+	for _, source := range sources {
+		// Visit ids are per database, so each profile's chains are built separately.
+		for _, path := range source.paths {
+			visits, err := utils.GetHistoryFromPaths(ctx, source.browserImpl, []history.HistoryPathEntry{path}, cfg.StartTime, cfg.EndTime, cfg.Concurrency, shouldLog(cfg))
+			if err != nil {
+				if explicit || cfg.Strict || ctx.Err() != nil {
+					return nil, fmt.Errorf("failed to read %s history: %v", source.name, err)
+				}
+				if shouldLog(cfg) {
+					fmt.Fprintf(os.Stderr, "Debug: Error retrieving %s history: %v\n", source.name, err)
+				}
+				continue
+			}
+			entries = append(entries, utils.ToChainOutputEntries(utils.NavigationChains(visits, matches), source.name)...)
+		}
+	}
+	return entries, nil
+}
+
+// visitSource returns the single profile of browserName matching profile by directory or display
+// name, the only database a visit id can be looked up in.
+func visitSource(sources []profileSource, browserName, profile string) (profileSource, error) {
+	var matches []profileSource
+	for _, source := range sources {
+		if source.name != browserName {
+			continue
+		}
+		for _, path := range source.paths {
+			if path.Profile == profile || path.ProfileName == profile {
+				match := source
+				match.paths = []history.HistoryPathEntry{path}
+				matches = append(matches, match)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return profileSource{}, fmt.Errorf("no %s profile %q found", browserName, profile)
+	case 1:
+		return matches[0], nil
+	default:
+		return profileSource{}, fmt.Errorf("%d %s profiles named %q found; select a single user", len(matches), browserName, profile)
+	}
+}
+
+// OutputChains writes navigation chains as JSON or as a header line per chain followed by its
+// visits, oldest first.
+func (s *historyService) OutputChains(entries []history.ChainOutputEntry, cfg *config.Config, writer io.Writer) {
+	if cfg.JSONOutput {
+		writeJSON(entries, cfg, writer)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(writer, "No navigation chains found.")
+		return
+	}
+
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Fprintln(writer)
+		}
+		fmt.Fprintf(writer, "[%s] [%s]", entry.Browser, entry.Profile)
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		fmt.Fprintln(writer)
+		for _, visit := range entry.Visits {
+			title := visit.Title
+			if title == "" {
+				title = "(no title)"
+			}
+			fmt.Fprintf(writer, "  %-30s %-50s (%s) [%d] [%s] [%s]\n",
+				visit.Timestamp,
+				title,
+				visit.URL,
+				visit.VisitID,
+				visit.VisitType,
+				visit.NativeVisitType)
+		}
+	}
+}
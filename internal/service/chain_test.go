@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockChainBrowser returns a search that redirected to an article.
+type mockChainBrowser struct {
+	mockDirectoryBrowser
+}
+
+func (m *mockChainBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	return history.Values([]history.HistoryEntry{
+		{URL: "https://go.dev/blog/", VisitID: 3, ReferrerVisitID: 2, ReferrerURL: "https://go.dev/blog", VisitType: "LINK", Timestamp: endTime, Profile: profile},
+		{URL: "https://go.dev/blog", VisitID: 2, ReferrerVisitID: 1, ReferrerURL: "https://www.google.com/search?q=go+blog", VisitType: "LINK", Timestamp: endTime.Add(-time.Second), Profile: profile},
+		{URL: "https://www.google.com/search?q=go+blog", VisitID: 1, VisitType: "TYPED", Timestamp: endTime.Add(-time.Minute), Profile: profile},
+	}, nil)
+}
+
+// mockTwoProfileChainBrowser finds two profiles whose databases both hold the same visit ids.
+type mockTwoProfileChainBrowser struct {
+	mockChainBrowser
+}
+
+func (m *mockTwoProfileChainBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	return []history.HistoryPathEntry{
+		{Profile: "Default", ProfileName: "Person 1", Path: m.dbPath},
+		{Profile: "Profile 1", ProfileName: "Person 2", Path: m.dbPath},
+	}, nil
+}
+
+func TestChainService_GetChains(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+
+	service := NewChainService(map[string]browser.Browser{
+		"chrome": &mockChainBrowser{mockDirectoryBrowser{dbPath: dbPath}},
+	})
+	endTime := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	profileDirs := []config.ProfileDir{{Browser: "chrome", Path: "/copies/chrome"}}
+
+	t.Run("URL", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: profileDirs}
+		entries, err := service.GetChains(context.Background(), cfg, nil, ChainTarget{URL: "https://go.dev/blog/"})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "chrome", entries[0].Browser)
+		assert.Equal(t, "Person 1", entries[0].Profile)
+		var urls []string
+		for _, visit := range entries[0].Visits {
+			urls = append(urls, visit.URL)
+		}
+		assert.Equal(t, []string{"https://www.google.com/search?q=go+blog", "https://go.dev/blog", "https://go.dev/blog/"}, urls)
+	})
+
+	t.Run("VisitID", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: profileDirs}
+		entries, err := service.GetChains(context.Background(), cfg, []string{"chrome"}, ChainTarget{VisitID: 2, Profile: "Default"})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Len(t, entries[0].Visits, 2)
+	})
+
+	t.Run("VisitIDNeedsBrowserAndProfile", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: profileDirs}
+		_, err := service.GetChains(context.Background(), cfg, []string{"chrome"}, ChainTarget{VisitID: 2})
+		assert.ErrorContains(t, err, "a visit id needs a single browser and a profile")
+		_, err = service.GetChains(context.Background(), cfg, nil, ChainTarget{VisitID: 2, Profile: "Default"})
+		assert.ErrorContains(t, err, "a visit id needs a single browser and a profile")
+		_, err = service.GetChains(context.Background(), cfg, []string{"chrome"}, ChainTarget{VisitID: 2, Profile: "Missing"})
+		assert.ErrorContains(t, err, `no chrome profile "Missing" found`)
+	})
+
+	t.Run("NoTarget", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: profileDirs}
+		_, err := service.GetChains(context.Background(), cfg, nil, ChainTarget{})
+		assert.ErrorContains(t, err, "a URL or visit id is required")
+	})
+}
+
+func TestChainService_VisitIDSharedByProfiles(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+	service := NewChainService(map[string]browser.Browser{
+		"chrome": &mockTwoProfileChainBrowser{mockChainBrowser{mockDirectoryBrowser{dbPath: dbPath}}},
+	})
+	endTime := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: []config.ProfileDir{{Browser: "chrome", Path: "/copies/chrome"}}}
+
+	// Both profiles hold a visit 3; only the chain of the named profile is returned.
+	for _, profile := range []string{"Profile 1", "Person 2"} {
+		entries, err := service.GetChains(context.Background(), cfg, []string{"chrome"}, ChainTarget{VisitID: 3, Profile: profile})
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "Person 2", entries[0].Profile)
+			assert.Len(t, entries[0].Visits, 3)
+		}
+	}
+
+	// A URL is matched in every profile.
+	entries, err := service.GetChains(context.Background(), cfg, nil, ChainTarget{URL: "https://go.dev/blog/"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestChainService_OutputChains(t *testing.T) {
+	service := NewChainService(map[string]browser.Browser{})
+	entries := []history.ChainOutputEntry{{
+		Browser: "firefox",
+		Profile: "default-release",
+		Visits: []history.OutputEntry{
+			{Timestamp: "2025-04-06T11:59:59Z", URL: "https://go.dev/blog", VisitID: 2, VisitType: history.VisitRedirectPermanent, NativeVisitType: "TRANSITION_REDIRECT_PERMANENT"},
+			{Timestamp: "2025-04-06T12:00:00Z", Title: "The Go Blog", URL: "https://go.dev/blog/", VisitID: 3, VisitType: history.VisitLink, NativeVisitType: "TRANSITION_LINK"},
+		},
+	}}
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputChains(entries, &config.Config{}, &buf)
+		assert.Equal(t, "[firefox] [default-release]\n"+
+			"  2025-04-06T11:59:59Z           (no title)                                         (https://go.dev/blog) [2] [redirect_permanent] [TRANSITION_REDIRECT_PERMANENT]\n"+
+			"  2025-04-06T12:00:00Z           The Go Blog                                        (https://go.dev/blog/) [3] [link] [TRANSITION_LINK]\n", buf.String())
+	})
+
+	t.Run("NoEntries", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputChains(nil, &config.Config{}, &buf)
+		assert.Equal(t, "No navigation chains found.\n", buf.String())
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/utils"
+)
+
+// DownloadService retrieves downloads from the same profiles HistoryService reads history from.
+type DownloadService interface {
+	GetDownloads(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.DownloadOutputEntry, error)
+	OutputDownloads(entries []history.DownloadOutputEntry, cfg *config.Config, writer io.Writer)
+}
+
+// Ensure historyService implements the interface
+var _ DownloadService = (*historyService)(nil)
+
+// NewDownloadService creates a DownloadService over the given browsers, or every registered browser when nil.
+func NewDownloadService(browserMap map[string]browser.Browser) DownloadService {
+	return NewHistoryService(browserMap).(*historyService)
+}
+
+// GetDownloads reads the downloads started within cfg's time range from the selected
+// browsers' profiles, honouring the same profile directory, archive, root and user selection
+// as GetHistory.
+func (s *historyService) GetDownloads(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.DownloadOutputEntry, error) {
+	resolveTimeRange(cfg)
+	sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(downloadBrowser browser.DownloadBrowser, path history.HistoryPathEntry) ([]history.DownloadEntry, error) {
+		return utils.ProfileDownloads(ctx, downloadBrowser, path, cfg.StartTime, cfg.EndTime, shouldLog(cfg))
+	}
+	return readProfiles(ctx, cfg, sources, explicit, "downloads", read, utils.ToDownloadOutputEntries)
+}
+
+// OutputDownloads writes downloads as JSON or as one text line per download.
+func (s *historyService) OutputDownloads(entries []history.DownloadOutputEntry, cfg *config.Config, writer io.Writer) {
+	if cfg.JSONOutput {
+		writeJSON(entries, cfg, writer)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(writer, "No downloads found.")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%-30s %-50s (%s) [%s] [%d/%d] [%s] [%s] [%s]",
+			entry.StartTime,
+			entry.TargetPath,
+			entry.URL,
+			entry.State,
+			entry.ReceivedBytes,
+			entry.TotalBytes,
+			entry.DangerType,
+			entry.Browser,
+			entry.Profile)
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		fmt.Fprintln(writer)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDownloadBrowser returns one download per profile, recording the requested time range.
+type mockDownloadBrowser struct {
+	mockDirectoryBrowser
+	startTime time.Time
+}
+
+func (m *mockDownloadBrowser) ExtractDownloads(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.DownloadEntry, error) {
+	m.startTime = startTime
+	return []history.DownloadEntry{{
+		TargetPath: "/home/bob/Downloads/go.tar.gz",
+		URLChain:   []string{"https://go.dev/dl/go.tar.gz", "https://dl.google.com/go.tar.gz"},
+		StartTime:  endTime,
+		State:      "COMPLETE",
+		Profile:    profile,
+	}}, nil
+}
+
+func TestDownloadService_GetDownloads(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+
+	downloadBrowser := &mockDownloadBrowser{mockDirectoryBrowser: mockDirectoryBrowser{dbPath: dbPath}}
+	service := NewDownloadService(map[string]browser.Browser{
+		"chrome":  downloadBrowser,
+		"edge":    &mockDirectoryBrowser{dbPath: dbPath},
+		"firefox": new(MockBrowser),
+	})
+	endTime := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ProfileDir", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: []config.ProfileDir{{Browser: "chrome", Path: "/copies/chrome"}}}
+		entries, err := service.GetDownloads(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "https://dl.google.com/go.tar.gz", entries[0].URL)
+		assert.Equal(t, "2025-04-06T12:00:00Z", entries[0].StartTime)
+		assert.Equal(t, "Person 1", entries[0].Profile)
+		assert.Equal(t, "chrome", entries[0].Browser)
+		assert.Equal(t, endTime.AddDate(0, 0, -7), downloadBrowser.startTime)
+	})
+
+	t.Run("UnsupportedBrowser", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: []config.ProfileDir{{Browser: "edge", Path: "/copies/edge"}}}
+		_, err := service.GetDownloads(context.Background(), cfg, nil)
+		assert.ErrorContains(t, err, `browser "edge" does not support downloads`)
+	})
+
+	t.Run("SkipsUnsupportedDefaults", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime}
+		entries, err := service.GetDownloads(context.Background(), cfg, []string{"firefox"})
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestDownloadService_OutputDownloads(t *testing.T) {
+	service := NewDownloadService(map[string]browser.Browser{})
+	entries := []history.DownloadOutputEntry{{
+		StartTime:     "2025-04-06T12:00:00Z",
+		TargetPath:    "/home/bob/Downloads/go.tar.gz",
+		URL:           "https://dl.google.com/go.tar.gz",
+		ReceivedBytes: 100,
+		TotalBytes:    100,
+		DangerType:    "NOT_DANGEROUS",
+		State:         "COMPLETE",
+		Browser:       "chrome",
+		Profile:       "Default",
+	}}
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputDownloads(entries, &config.Config{}, &buf)
+		assert.Equal(t, "2025-04-06T12:00:00Z           /home/bob/Downloads/go.tar.gz                      (https://dl.google.com/go.tar.gz) [COMPLETE] [100/100] [NOT_DANGEROUS] [chrome] [Default]\n", buf.String())
+	})
+
+	t.Run("NoEntries", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputDownloads(nil, &config.Config{}, &buf)
+		assert.Equal(t, "No downloads found.\n", buf.String())
+	})
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/utils"
+)
+
+// SearchTermService retrieves the searches users ran from the same profiles HistoryService reads history from.
+type SearchTermService interface {
+	GetSearchTerms(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.SearchTermOutputEntry, error)
+	OutputSearchTerms(entries []history.SearchTermOutputEntry, cfg *config.Config, writer io.Writer)
+}
+
+// Ensure historyService implements the interface
+var _ SearchTermService = (*historyService)(nil)
+
+// NewSearchTermService creates a SearchTermService over the given browsers, or every registered browser when nil.
+func NewSearchTermService(browserMap map[string]browser.Browser) SearchTermService {
+	return NewHistoryService(browserMap).(*historyService)
+}
+
+// GetSearchTerms reads the searches run within cfg's time range from the selected browsers'
+// profiles: the terms Chromium records in keyword_search_terms and, for every browser, the
+// terms in visited search engine results URLs. A URL search matching a recorded keyword search
+// is reported once.
+func (s *historyService) GetSearchTerms(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.SearchTermOutputEntry, error) {
+	resolveTimeRange(cfg)
+	sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(browserImpl browser.Browser, path history.HistoryPathEntry) ([]history.SearchTermEntry, error) {
+		return profileSearchTerms(ctx, cfg, browserImpl, path)
+	}
+	return readProfiles(ctx, cfg, sources, explicit, "search terms", read, newestSearchTerms)
+}
+
+// newestSearchTerms returns the output entries of a browser's searches, newest first.
+func newestSearchTerms(searches []history.SearchTermEntry, browserName string) []history.SearchTermOutputEntry {
+	sort.SliceStable(searches, func(i, j int) bool {
+		return searches[i].Timestamp.After(searches[j].Timestamp)
+	})
+	return utils.ToSearchTermOutputEntries(searches, browserName)
+}
+
+// profileSearchTerms merges a profile's recorded keyword searches with those parsed from its
+// history, reading both from a single copy of its database.
+func profileSearchTerms(ctx context.Context, cfg *config.Config, browserImpl browser.Browser, path history.HistoryPathEntry) ([]history.SearchTermEntry, error) {
+	historyDBPath, cleanup, err := utils.PrepareProfileFile(ctx, path, path.Path, shouldLog(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare database file at %s: %v", path.Path, err)
+	}
+	defer cleanup()
+
+	var searches []history.SearchTermEntry
+	if searchBrowser, ok := browserImpl.(browser.SearchTermBrowser); ok {
+		searches, err = utils.ExtractProfileSearchTerms(ctx, searchBrowser, historyDBPath, path, cfg.StartTime, cfg.EndTime, shouldLog(cfg))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	historyEntries, err := history.Collect(utils.ExtractProfileHistory(ctx, browserImpl, historyDBPath, path, cfg.StartTime, cfg.EndTime, shouldLog(cfg)))
+	if err != nil {
+		return nil, err
+	}
+	type visit struct {
+		profile, url string
+		micros       int64
+	}
+	recorded := map[visit]bool{}
+	for _, search := range searches {
+		recorded[visit{search.Profile, search.URL, search.Timestamp.UnixMicro()}] = true
+	}
+	for _, search := range utils.SearchTermsFromHistory(historyEntries) {
+		if !recorded[visit{search.Profile, search.URL, search.Timestamp.UnixMicro()}] {
+			searches = append(searches, search)
+		}
+	}
+	return searches, nil
+}
+
+// OutputSearchTerms writes search terms as JSON or as one text line per search.
+func (s *historyService) OutputSearchTerms(entries []history.SearchTermOutputEntry, cfg *config.Config, writer io.Writer) {
+	if cfg.JSONOutput {
+		writeJSON(entries, cfg, writer)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(writer, "No search terms found.")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%-30s %-12s %-50s [%s] [%s] [%s]",
+			entry.Timestamp,
+			entry.Engine,
+			entry.Term,
+			entry.Source,
+			entry.Browser,
+			entry.Profile)
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		fmt.Fprintln(writer)
+	}
+}
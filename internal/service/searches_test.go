@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSearchBrowser records one keyword search and visits its results page plus a second search
+// engine. It keeps the database paths each extraction was given.
+type mockSearchBrowser struct {
+	mockDirectoryBrowser
+	historyDBPath, searchDBPath string
+}
+
+const mockSearchURL = "https://www.google.com/search?q=go+iterators"
+
+func (m *mockSearchBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	m.historyDBPath = dbPath
+	return history.Values([]history.HistoryEntry{
+		{URL: mockSearchURL, Profile: profile, Timestamp: endTime},
+		{URL: "https://duckduckgo.com/?q=sqlite", Profile: profile, Timestamp: endTime.Add(-time.Hour)},
+		{URL: "https://go.dev/", Profile: profile, Timestamp: endTime.Add(-2 * time.Hour)},
+	}, nil)
+}
+
+func (m *mockSearchBrowser) ExtractSearchTerms(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, verbose bool) ([]history.SearchTermEntry, error) {
+	m.searchDBPath = dbPath
+	return []history.SearchTermEntry{{
+		Engine:    "google",
+		Term:      "go iterators",
+		URL:       mockSearchURL,
+		Timestamp: endTime,
+		Source:    history.SearchSourceKeyword,
+		Profile:   profile,
+	}}, nil
+}
+
+func TestSearchTermService_GetSearchTerms(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+
+	searchBrowser := &mockSearchBrowser{mockDirectoryBrowser: mockDirectoryBrowser{dbPath: dbPath}}
+	service := NewSearchTermService(map[string]browser.Browser{
+		"chrome": searchBrowser,
+		"edge":   &mockDirectoryBrowser{dbPath: dbPath},
+	})
+	endTime := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+
+	t.Run("MergesKeywordAndURLSearches", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: []config.ProfileDir{{Browser: "chrome", Path: "/copies/chrome"}}}
+		entries, err := service.GetSearchTerms(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []history.SearchTermOutputEntry{
+			{
+				Timestamp: "2025-04-06T12:00:00Z",
+				Engine:    "google",
+				Term:      "go iterators",
+				URL:       mockSearchURL,
+				Source:    history.SearchSourceKeyword,
+				Browser:   "chrome",
+				Profile:   "Person 1",
+			},
+			{
+				Timestamp: "2025-04-06T11:00:00Z",
+				Engine:    "duckduckgo",
+				Term:      "sqlite",
+				URL:       "https://duckduckgo.com/?q=sqlite",
+				Source:    history.SearchSourceURL,
+				Browser:   "chrome",
+				Profile:   "Person 1",
+			},
+		}, entries)
+		// Keyword searches and history are read from the same copy of the database.
+		assert.NotEmpty(t, searchBrowser.searchDBPath)
+		assert.Equal(t, searchBrowser.searchDBPath, searchBrowser.historyDBPath)
+	})
+
+	t.Run("HistoryOnlyBrowser", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 7, EndTime: endTime, ProfileDirs: []config.ProfileDir{{Browser: "edge", Path: "/copies/edge"}}}
+		entries, err := service.GetSearchTerms(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestSearchTermService_OutputSearchTerms(t *testing.T) {
+	service := NewSearchTermService(map[string]browser.Browser{})
+	entries := []history.SearchTermOutputEntry{{
+		Timestamp: "2025-04-06T12:00:00Z",
+		Engine:    "google",
+		Term:      "go iterators",
+		URL:       mockSearchURL,
+		Source:    history.SearchSourceURL,
+		Browser:   "firefox",
+		Profile:   "default-release",
+		User:      "bob",
+	}}
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputSearchTerms(entries, &config.Config{}, &buf)
+		assert.Equal(t, "2025-04-06T12:00:00Z           google       go iterators                                       [url] [firefox] [default-release] [bob]\n", buf.String())
+	})
+
+	t.Run("NoEntries", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputSearchTerms(nil, &config.Config{}, &buf)
+		assert.Equal(t, "No search terms found.\n", buf.String())
+	})
+}
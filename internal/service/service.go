@@ -1,10 +1,16 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
+	"runtime"
+	"slices"
+	"time"
 
 	"github.com/lotekdan/go-browser-history/internal/browser"
 	"github.com/lotekdan/go-browser-history/internal/config"
@@ -14,8 +20,26 @@ import (
 
 // Define the HistoryService interface
 type HistoryService interface {
-	GetHistory(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error)
+	GetHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error)
+	// StreamHistory returns the same entries as GetHistory as a sequence that reads each
+	// profile's database only while it is iterated. An error ends the sequence. A non-nil report
+	// is filled in with how each located profile was read as the sequence is iterated.
+	StreamHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string, report *SourceReport) iter.Seq2[history.OutputEntry, error]
 	OutputResults(entries []history.OutputEntry, cfg *config.Config, writer io.Writer)
+	// WriteResults writes entries as they are produced, returning the first error the sequence
+	// yields. Nothing is written until the first entry or the end of the sequence.
+	WriteResults(entries iter.Seq2[history.OutputEntry, error], cfg *config.Config, writer io.Writer) error
+	// WriteEnvelope writes entries and the report filled in while reading them as a single JSON
+	// object with "entries" and "sources" fields.
+	WriteEnvelope(entries iter.Seq2[history.OutputEntry, error], report *SourceReport, cfg *config.Config, writer io.Writer) error
+	// OutputSources writes a text summary of report, one line per browser profile.
+	OutputSources(report *SourceReport, writer io.Writer)
+}
+
+// SourceReport records, for every browser and profile a history read located, whether it was
+// found, how many entries were read from it and why reading it failed.
+type SourceReport struct {
+	Sources []history.SourceResult
 }
 
 // Concrete implementation of HistoryService
@@ -36,106 +60,519 @@ func NewHistoryService(browserMap map[string]browser.Browser) HistoryService {
 	}
 }
 
+// initializeBrowsers builds the browser map from the browser registry.
 func initializeBrowsers() map[string]browser.Browser {
-	return map[string]browser.Browser{
-		"chrome":  browser.NewChromeBrowser(),
-		"edge":    browser.NewEdgeBrowser(),
-		"firefox": browser.NewFirefoxBrowser(),
-		"brave":   browser.NewBraveBrowser(),
-	}
+	return browser.All()
 }
 
 // Implement GetHistory method
-func (s *historyService) GetHistory(cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+func (s *historyService) GetHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.OutputEntry, error) {
+	return history.Collect(s.StreamHistory(ctx, cfg, selectedBrowsers, nil))
+}
+
+// StreamHistory reads the history of the profiles cfg selects newest first, copying and
+// querying up to cfg.Concurrency profile databases at once. Read errors end the sequence for
+// profiles the caller named explicitly, in strict mode or once ctx is cancelled; other profiles
+// that cannot be read are skipped without holding up the rest and recorded in report.
+func (s *historyService) StreamHistory(ctx context.Context, cfg *config.Config, selectedBrowsers []string, report *SourceReport) iter.Seq2[history.OutputEntry, error] {
+	resolveTimeRange(cfg)
+	if report == nil {
+		report = &SourceReport{}
+	}
+	return func(yield func(history.OutputEntry, error) bool) {
+		sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+		defer cleanup()
+		if err != nil {
+			yield(history.OutputEntry{}, err)
+			return
+		}
+
+		// Merge every profile's history, tagged with its browser, in timestamp order. results
+		// holds the index in report of each merged profile.
+		report.Sources = nil
+		var results []int
+		var seqs []iter.Seq2[browserEntry, error]
+		for _, source := range sources {
+			if len(source.paths) == 0 {
+				result := history.SourceResult{Browser: source.name, User: source.user}
+				if source.err != nil {
+					result.Error = source.err.Error()
+				}
+				report.Sources = append(report.Sources, result)
+				continue
+			}
+			for _, path := range source.paths {
+				results = append(results, len(report.Sources))
+				report.Sources = append(report.Sources, history.SourceResult{
+					Browser: source.name,
+					Profile: path.ProfileName,
+					Channel: path.Channel,
+					User:    path.User,
+					Path:    path.Path,
+					Found:   true,
+				})
+				seqs = append(seqs, withBrowser(utils.ProfileHistory(ctx, source.browserImpl, path, cfg.StartTime, cfg.EndTime, shouldLog(cfg)), source.name, len(seqs)))
+			}
+		}
+		failed := ""
+		skip := func(index int, err error) bool {
+			result := &report.Sources[results[index]]
+			result.Error = err.Error()
+			if explicit || cfg.Strict || ctx.Err() != nil {
+				failed = result.Browser
+				return false
+			}
+			if shouldLog(cfg) {
+				fmt.Fprintf(os.Stderr, "Debug: Error retrieving %s history: %v\n", result.Browser, err)
+			}
+			return true
+		}
+		timestamp := func(entry browserEntry) time.Time { return entry.Timestamp }
+
+		for entry, err := range utils.MergeByTimestamp(seqs, timestamp, cfg.Concurrency, skip) {
+			if err != nil {
+				yield(history.OutputEntry{}, fmt.Errorf("failed to read %s history: %v", failed, err))
+				return
+			}
+			report.Sources[results[entry.source]].Entries++
+			if !yield(utils.ToOutputEntry(entry.HistoryEntry, entry.browser), nil) {
+				return
+			}
+		}
+	}
+}
+
+// browserEntry is a history entry, the browser it was read from and the index of the profile
+// sequence it came from.
+type browserEntry struct {
+	history.HistoryEntry
+	browser string
+	source  int
+}
+
+// withBrowser tags each entry of a profile's history with the browser it was read from and the
+// index of the profile's sequence.
+func withBrowser(seq iter.Seq2[history.HistoryEntry, error], browserName string, source int) iter.Seq2[browserEntry, error] {
+	return func(yield func(browserEntry, error) bool) {
+		for entry, err := range seq {
+			if !yield(browserEntry{HistoryEntry: entry, browser: browserName, source: source}, err) {
+				return
+			}
+		}
+	}
+}
+
+// profileSource is a browser and the profiles located for it. A browser with no profiles in the
+// environment of user has no paths, and err holds why they could not be located when the
+// browser's data is there but unreadable.
+type profileSource struct {
+	name        string
+	browserImpl browser.Browser
+	paths       []history.HistoryPathEntry
+	user        string
+	err         error
+}
+
+// locateProfiles finds the profiles of the selected browsers, or of every browser when none are
+// selected, in the default locations of each environment cfg selects. Browsers without
+// profiles are returned without paths, along with the error when their profiles exist but
+// could not be read, which fails the lookup in strict mode.
+func (s *historyService) locateProfiles(cfg *config.Config, selectedBrowsers []string) ([]profileSource, error) {
 	browserList := s.resolveBrowsers(selectedBrowsers)
 	if len(browserList) == 0 {
 		return nil, fmt.Errorf("no valid browsers specified")
 	}
+	envs, err := environments(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []profileSource
+	for _, env := range envs {
+		for _, name := range browserList {
+			browserImpl := s.browserMap[name]
+			historyDBPaths, err := historyPaths(browserImpl, env)
+			if err != nil {
+				if shouldLog(cfg) {
+					fmt.Fprintf(os.Stderr, "Debug: Error finding %s history file: %v\n", name, err)
+				}
+				if profilesMissing(err) {
+					err = nil
+				} else if cfg.Strict {
+					if env.User != "" {
+						return nil, fmt.Errorf("failed to locate %s profiles of %s: %v", name, env.User, err)
+					}
+					return nil, fmt.Errorf("failed to locate %s profiles: %v", name, err)
+				}
+				sources = append(sources, profileSource{name: name, browserImpl: browserImpl, user: env.User, err: err})
+				continue
+			}
+			if shouldLog(cfg) && len(browserList) > 1 {
+				fmt.Fprintf(os.Stderr, "Debug: Using %s database path: %v\n", name, historyDBPaths)
+			}
+			sources = append(sources, profileSource{name: name, browserImpl: browserImpl, paths: historyDBPaths, user: env.User})
+		}
+	}
+	return sources, nil
+}
 
-	cfg.StartTime = cfg.EndTime.AddDate(0, 0, -cfg.HistoryDays)
-	return s.fetchHistory(cfg, browserList)
+// profilesMissing reports whether a profile lookup failed only because the browser is not
+// installed, rather than because its profiles could not be read.
+func profilesMissing(err error) bool {
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, browser.ErrUnsupportedOS)
 }
 
-func (s *historyService) resolveBrowsers(selectedBrowsers []string) []string {
-	if len(selectedBrowsers) == 0 {
-		return []string{"chrome", "edge", "brave", "firefox"}
+// locateProfileDir finds the profiles in an explicit profile directory.
+func (s *historyService) locateProfileDir(cfg *config.Config, dir config.ProfileDir) (profileSource, error) {
+	browserImpl, exists := s.browserMap[dir.Browser]
+	if !exists {
+		return profileSource{}, fmt.Errorf("unknown browser %q for profile directory %s", dir.Browser, dir.Path)
 	}
-	var validBrowsers []string
-	for _, name := range selectedBrowsers {
-		if _, exists := s.browserMap[name]; exists {
-			validBrowsers = append(validBrowsers, name)
-		}
+	dirBrowser, ok := browserImpl.(browser.DirectoryBrowser)
+	if !ok {
+		return profileSource{}, fmt.Errorf("browser %q does not support explicit profile directories", dir.Browser)
 	}
-	return validBrowsers
+	historyDBPaths, err := dirBrowser.GetHistoryPathsFrom(dir.Path)
+	if err != nil {
+		return profileSource{}, fmt.Errorf("no %s profiles found in %s: %v", dir.Browser, dir.Path, err)
+	}
+	if shouldLog(cfg) {
+		fmt.Fprintf(os.Stderr, "Debug: Using %s database path: %v\n", dir.Browser, historyDBPaths)
+	}
+	return profileSource{name: dir.Browser, browserImpl: browserImpl, paths: historyDBPaths}, nil
 }
 
-func (s *historyService) fetchHistory(cfg *config.Config, browsers []string) ([]history.OutputEntry, error) {
-	var entries []history.OutputEntry
-	for _, name := range browsers {
-		browserImpl := s.browserMap[name]
-		historyDBPaths, err := browserImpl.GetHistoryPaths()
+// locateSources finds the profiles cfg selects: those in its explicit profile directories and
+// archives when any are given, otherwise the default locations of the selected browsers. The
+// returned cleanup removes any files extracted from archives; extraction stops when ctx is
+// cancelled. explicit reports whether the sources were named by the caller, in which case read
+// errors should not be skipped.
+func (s *historyService) locateSources(ctx context.Context, cfg *config.Config, selectedBrowsers []string) (sources []profileSource, cleanup func(), explicit bool, err error) {
+	if len(cfg.ProfileDirs) == 0 && len(cfg.Archives) == 0 {
+		sources, err = s.locateProfiles(cfg, selectedBrowsers)
+		return sources, func() {}, false, err
+	}
+
+	var cleanups []func()
+	cleanup = func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+	for _, dir := range cfg.ProfileDirs {
+		source, err := s.locateProfileDir(cfg, dir)
+		if err != nil {
+			return nil, cleanup, true, err
+		}
+		sources = append(sources, source)
+	}
+	for _, archivePath := range cfg.Archives {
+		profiles, archiveCleanup, err := utils.ExtractArchiveProfiles(ctx, archivePath, shouldLog(cfg))
+		cleanups = append(cleanups, archiveCleanup)
 		if err != nil {
+			return nil, cleanup, true, err
+		}
+		if len(profiles) == 0 {
+			return nil, cleanup, true, fmt.Errorf("no browser profiles found in archive %s", archivePath)
+		}
+		for _, profile := range profiles {
+			browserImpl, exists := s.browserMap[profile.Browser]
+			if !exists {
+				return nil, cleanup, true, fmt.Errorf("unknown browser %q for archive %s", profile.Browser, archivePath)
+			}
+			sources = append(sources, profileSource{name: profile.Browser, browserImpl: browserImpl, paths: []history.HistoryPathEntry{profile.HistoryPathEntry}})
+		}
+	}
+	return sources, cleanup, true, nil
+}
+
+// readProfiles reads each profile of the sources whose browser implements B with read, and
+// returns every source's entries converted with convert. A source whose browser does not
+// implement B fails the read when the caller named it and is skipped otherwise. Like
+// StreamHistory, a profile that cannot be read fails the read when the caller named it, in
+// strict mode or once ctx is cancelled; otherwise it is skipped, with the error logged, and the
+// remaining profiles are still read. kind names what is read in messages, such as "bookmarks".
+func readProfiles[B, T, O any](ctx context.Context, cfg *config.Config, sources []profileSource, explicit bool, kind string, read func(B, history.HistoryPathEntry) ([]T, error), convert func([]T, string) []O) ([]O, error) {
+	var output []O
+	for _, source := range sources {
+		browserImpl, ok := source.browserImpl.(B)
+		if !ok {
+			if explicit {
+				return nil, fmt.Errorf("browser %q does not support %s", source.name, kind)
+			}
 			if shouldLog(cfg) {
-				fmt.Fprintf(os.Stderr, "Debug: Error finding %s history file: %v\n", name, err)
+				fmt.Fprintf(os.Stderr, "Debug: Skipping %s, %s are not supported\n", source.name, kind)
 			}
 			continue
 		}
-		if shouldLog(cfg) && len(browsers) > 1 {
-			fmt.Fprintf(os.Stderr, "Debug: Using %s database path: %s\n", name, historyDBPaths)
+		var entries []T
+		for _, path := range source.paths {
+			profileEntries, err := read(browserImpl, path)
+			if err != nil {
+				if explicit || cfg.Strict || ctx.Err() != nil {
+					return nil, fmt.Errorf("failed to read %s %s: %v", source.name, kind, err)
+				}
+				if shouldLog(cfg) {
+					fmt.Fprintf(os.Stderr, "Debug: Error retrieving %s %s of profile %s: %v\n", source.name, kind, path.ProfileName, err)
+				}
+				continue
+			}
+			entries = append(entries, profileEntries...)
 		}
+		output = append(output, convert(entries, source.name)...)
+	}
+	return output, nil
+}
+
+// environments returns the Environments default browser locations are resolved against: the
+// running user, a single home rebased onto cfg.Root, or every user home in all-users mode.
+func environments(cfg *config.Config) ([]browser.Environment, error) {
+	targetOS := runtime.GOOS
+	if cfg.Root != "" && cfg.TargetOS != "" {
+		targetOS = cfg.TargetOS
+	}
 
-		browserEntries, err := utils.GetBrowserHistory(browserImpl, cfg.StartTime, cfg.EndTime, shouldLog(cfg))
+	if cfg.AllUsers || len(cfg.Users) > 0 {
+		userEnvs, err := browser.UserEnvironments(cfg.Root, targetOS)
 		if err != nil {
-			if shouldLog(cfg) {
-				fmt.Fprintf(os.Stderr, "Debug: Error retrieving %s history: %v\n", name, err)
+			return nil, fmt.Errorf("failed to enumerate users: %v", err)
+		}
+		var envs []browser.Environment
+		for _, env := range userEnvs {
+			if len(cfg.Users) == 0 || slices.Contains(cfg.Users, env.User) {
+				envs = append(envs, env)
 			}
-			continue
 		}
-		entries = append(entries, utils.ToOutputEntries(browserEntries, name)...)
+		if len(envs) == 0 {
+			return nil, fmt.Errorf("no matching users found")
+		}
+		return envs, nil
 	}
-	return entries, nil
+
+	if cfg.Root == "" {
+		return []browser.Environment{browser.CurrentEnvironment()}, nil
+	}
+	env, err := browser.RootedEnvironment(cfg.Root, targetOS, cfg.Home)
+	if err != nil {
+		return nil, err
+	}
+	return []browser.Environment{env}, nil
 }
 
-// Implement OutputResults method
-func (s *historyService) OutputResults(entries []history.OutputEntry, cfg *config.Config, writer io.Writer) {
-	if cfg.JSONOutput {
-		if cfg.PrettyPrint {
-			jsonData, err := json.MarshalIndent(entries, "", "  ")
-			if err != nil {
-				fmt.Fprintln(writer, "[]") // Include newline on error
-				return
+// historyPaths locates a browser's profiles in env, falling back to the browser's own lookup
+// when env is the running system.
+func historyPaths(browserImpl browser.Browser, env browser.Environment) ([]history.HistoryPathEntry, error) {
+	if envBrowser, ok := browserImpl.(browser.EnvironmentBrowser); ok {
+		return envBrowser.GetHistoryPathsIn(env)
+	}
+	if env.Vars != nil {
+		return nil, fmt.Errorf("browser does not support reading another user's or a mounted root's profiles")
+	}
+	return browserImpl.GetHistoryPaths()
+}
+
+func (s *historyService) resolveBrowsers(selectedBrowsers []string) []string {
+	var validBrowsers []string
+	if len(selectedBrowsers) == 0 {
+		for _, name := range browser.Names() {
+			if _, exists := s.browserMap[name]; exists {
+				validBrowsers = append(validBrowsers, name)
 			}
-			fmt.Fprintln(writer, string(jsonData))
-			return
 		}
-		jsonData, err := json.Marshal(entries)
-		if err != nil {
-			fmt.Fprintln(writer, "[]") // Include newline on error
-			return
+		return validBrowsers
+	}
+	for _, name := range selectedBrowsers {
+		if _, exists := s.browserMap[name]; exists {
+			validBrowsers = append(validBrowsers, name)
 		}
-		fmt.Fprintln(writer, string(jsonData)) // Use Fprintln to add newline
-		return
 	}
+	return validBrowsers
+}
 
-	if len(entries) == 0 {
-		fmt.Fprintln(writer, "No history entries found.")
-		return
+// Implement OutputResults method
+func (s *historyService) OutputResults(entries []history.OutputEntry, cfg *config.Config, writer io.Writer) {
+	s.WriteResults(history.Values(entries, nil), cfg, writer)
+}
+
+// WriteResults writes entries as a JSON array or as one text line per entry.
+func (s *historyService) WriteResults(entries iter.Seq2[history.OutputEntry, error], cfg *config.Config, writer io.Writer) error {
+	if cfg.JSONOutput {
+		return writeJSONSeq(entries, cfg, writer)
 	}
 
-	for _, entry := range entries {
+	count := 0
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		count++
 		title := entry.Title
 		if title == "" {
 			title = "(no title)"
 		}
-		fmt.Fprintf(writer, "%-30s %-50s (%s) [%d] [%d] [%s] [%s] [%s]\n",
+		fmt.Fprintf(writer, "%-30s %-50s (%s) [%d] [%d] [%s] [%s] [%s] [%s]",
 			entry.Timestamp,
 			title,
 			entry.URL,
 			entry.VisitCount,
 			entry.Typed,
 			entry.VisitType,
+			entry.NativeVisitType,
 			entry.Browser,
 			entry.Profile)
+		if entry.Transition != nil {
+			fmt.Fprintf(writer, " [%d]", entry.Transition.Raw)
+		}
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		if _, err := fmt.Fprintln(writer); err != nil {
+			return err
+		}
+	}
+
+	if count == 0 {
+		fmt.Fprintln(writer, "No history entries found.")
+	}
+	return nil
+}
+
+// WriteEnvelope writes {"entries": [...], "sources": [...]}, streaming the entries as they are
+// read. The sources are written once the entries end, so they include every source read. When
+// reading fails after entries were written, the envelope is completed with an "error" field and
+// the error is returned.
+func (s *historyService) WriteEnvelope(entries iter.Seq2[history.OutputEntry, error], report *SourceReport, cfg *config.Config, writer io.Writer) error {
+	open, separator, errorSeparator, closing, indent := `{"entries":`, `,"sources":`, `,"error":`, "}", ""
+	if cfg.PrettyPrint {
+		open, separator, errorSeparator, closing, indent = "{\n  \"entries\": ", ",\n  \"sources\": ", ",\n  \"error\": ", "\n}", "  "
+	}
+
+	started, readErr := writeJSONArray(entries, cfg, writer, open, indent)
+	if readErr != nil && !started {
+		return readErr
+	}
+	sources := report.Sources
+	if sources == nil {
+		sources = []history.SourceResult{}
+	}
+	var jsonData []byte
+	var err error
+	if cfg.PrettyPrint {
+		jsonData, err = json.MarshalIndent(sources, indent, "  ")
+	} else {
+		jsonData, err = json.Marshal(sources)
+	}
+	if err != nil {
+		return err
+	}
+	// An error after entries were written is reported in the envelope so the output stays valid JSON.
+	trailer := separator + string(jsonData)
+	if readErr != nil {
+		message, _ := json.Marshal(readErr.Error())
+		trailer += errorSeparator + string(message)
+	}
+	if _, err := fmt.Fprintln(writer, trailer+closing); err != nil {
+		return err
+	}
+	return readErr
+}
+
+// OutputSources writes one line per source: its browser and profile followed by the number of
+// entries read, the error that stopped it being read, or "not found".
+func (s *historyService) OutputSources(report *SourceReport, writer io.Writer) {
+	for _, source := range report.Sources {
+		name := source.Browser
+		if source.User != "" {
+			name = source.User + "/" + name
+		}
+		status := fmt.Sprintf("%d entries", source.Entries)
+		switch {
+		case source.Error != "":
+			status = "error: " + source.Error
+		case !source.Found:
+			status = "not found"
+		}
+		fmt.Fprintf(writer, "%-20s %-20s %s\n", name, source.Profile, status)
+	}
+}
+
+// writeJSON writes v as a single JSON document, indented when pretty printing is enabled.
+func writeJSON(v any, cfg *config.Config, writer io.Writer) {
+	var jsonData []byte
+	var err error
+	if cfg.PrettyPrint {
+		jsonData, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		jsonData, err = json.Marshal(v)
+	}
+	if err != nil {
+		fmt.Fprintln(writer, "[]") // Include newline on error
+		return
+	}
+	fmt.Fprintln(writer, string(jsonData)) // Use Fprintln to add newline
+}
+
+// writeJSONSeq writes a sequence as a JSON array one element at a time, in the same layout as
+// writeJSON, and returns the first error the sequence yields or writing fails with.
+func writeJSONSeq[T any](seq iter.Seq2[T, error], cfg *config.Config, writer io.Writer) error {
+	started, err := writeJSONArray(seq, cfg, writer, "", "")
+	if started {
+		if _, writeErr := fmt.Fprintln(writer); err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// writeJSONArray writes prefix followed by a sequence as a JSON array, without a trailing
+// newline. Nothing is written until the first element or the end of the sequence. If the
+// sequence fails after elements were written, the array is still closed so the output remains
+// valid JSON. started reports whether anything was written. When pretty printing, every line
+// after the first is prefixed with indent.
+func writeJSONArray[T any](seq iter.Seq2[T, error], cfg *config.Config, writer io.Writer, prefix, indent string) (started bool, err error) {
+	open, separator, closing := prefix+"[", ",", "]"
+	if cfg.PrettyPrint {
+		open, separator, closing = prefix+"[\n"+indent+"  ", ",\n"+indent+"  ", "\n"+indent+"]"
+	}
+
+	count := 0
+	for entry, seqErr := range seq {
+		var jsonData []byte
+		if seqErr == nil {
+			if cfg.PrettyPrint {
+				jsonData, seqErr = json.MarshalIndent(entry, indent+"  ", "  ")
+			} else {
+				jsonData, seqErr = json.Marshal(entry)
+			}
+		}
+		if seqErr != nil {
+			if count > 0 {
+				io.WriteString(writer, closing)
+			}
+			return count > 0, seqErr
+		}
+		elementPrefix := separator
+		if count == 0 {
+			elementPrefix = open
+		}
+		if _, err := io.WriteString(writer, elementPrefix+string(jsonData)); err != nil {
+			return true, err
+		}
+		count++
+	}
+
+	if count == 0 {
+		closing = prefix + "[]"
+	}
+	_, err = io.WriteString(writer, closing)
+	return true, err
+}
+
+// resolveTimeRange sets cfg.StartTime to cfg.HistoryDays before cfg.EndTime unless the caller
+// gave an explicit start.
+func resolveTimeRange(cfg *config.Config) {
+	if !cfg.CustomRange {
+		cfg.StartTime = cfg.EndTime.AddDate(0, 0, -cfg.HistoryDays)
 	}
 }
 
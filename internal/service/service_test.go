@@ -1,8 +1,15 @@
 package service
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -22,10 +29,318 @@ func (m *MockBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
 	return nil, nil
 }
 
-func (m *MockBrowser) ExtractHistory(dbPath, profile string, startTime, endTime time.Time, debug bool) ([]history.HistoryEntry, error) {
+func (m *MockBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	return history.Values[history.HistoryEntry](nil, nil)
+}
+
+// mockDirectoryBrowser returns a fixed profile for any explicit directory.
+type mockDirectoryBrowser struct {
+	MockBrowser
+	dbPath string
+	dirs   []string
+}
+
+func (m *mockDirectoryBrowser) GetHistoryPathsFrom(dir string) ([]history.HistoryPathEntry, error) {
+	m.dirs = append(m.dirs, dir)
+	return []history.HistoryPathEntry{{Profile: "Default", ProfileName: "Person 1", Path: m.dbPath}}, nil
+}
+
+func (m *mockDirectoryBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	return history.Values([]history.HistoryEntry{{URL: "https://example.com", Profile: profile, Timestamp: endTime}}, nil)
+}
+
+func TestHistoryService_ProfileDirs(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+
+	dirBrowser := &mockDirectoryBrowser{dbPath: dbPath}
+	service := NewHistoryService(map[string]browser.Browser{
+		"chrome":  dirBrowser,
+		"firefox": new(MockBrowser),
+	})
+
+	t.Run("ReadsOnlyExplicitDirectories", func(t *testing.T) {
+		cfg := &config.Config{
+			HistoryDays: 1,
+			EndTime:     time.Now(),
+			ProfileDirs: []config.ProfileDir{{Browser: "chrome", Path: "/portable/User Data"}},
+		}
+		entries, err := service.GetHistory(context.Background(), cfg, []string{"firefox"})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "chrome", entries[0].Browser)
+		assert.Equal(t, "Person 1", entries[0].Profile)
+		assert.Equal(t, []string{"/portable/User Data"}, dirBrowser.dirs)
+	})
+
+	t.Run("UnknownBrowser", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "netscape", Path: "/tmp"}}}
+		_, err := service.GetHistory(context.Background(), cfg, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("UnsupportedBrowser", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "firefox", Path: "/tmp"}}}
+		_, err := service.GetHistory(context.Background(), cfg, nil)
+		assert.Error(t, err)
+	})
+}
+
+// writeZip writes an archive holding members, keyed by their path in the archive.
+func writeZip(t *testing.T, archivePath string, members map[string]string) {
+	file, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	writer := zip.NewWriter(file)
+	for name, contents := range members {
+		w, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, file.Close())
+}
+
+func TestHistoryService_Archives(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "profiles.zip")
+	writeZip(t, archivePath, map[string]string{"bob/.config/BraveSoftware/Brave-Browser/Default/History": "mock data"})
+
+	service := NewHistoryService(map[string]browser.Browser{"brave": &mockDirectoryBrowser{}})
+
+	t.Run("ReadsDetectedBrowser", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 1, EndTime: time.Now(), Archives: []string{archivePath}}
+		entries, err := service.GetHistory(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "brave", entries[0].Browser)
+		assert.Equal(t, "Default", entries[0].Profile)
+	})
+
+	t.Run("MissingArchive", func(t *testing.T) {
+		cfg := &config.Config{Archives: []string{filepath.Join(t.TempDir(), "missing.zip")}}
+		_, err := service.GetHistory(context.Background(), cfg, nil)
+		assert.Error(t, err)
+	})
+}
+
+// mockPathBrowser finds a single profile at a fixed path in the default locations.
+type mockPathBrowser struct {
+	MockBrowser
+	dbPath string
+}
+
+func (m *mockPathBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return []history.HistoryPathEntry{{Path: m.dbPath, ProfileName: "Default"}}, nil
+}
+
+func TestHistoryService_Cancelled(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+	service := NewHistoryService(map[string]browser.Browser{"chrome": &mockPathBrowser{dbPath: dbPath}})
+
+	// A cancelled read is reported rather than skipped like an unreadable default profile.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := service.GetHistory(ctx, &config.Config{HistoryDays: 1, EndTime: time.Now()}, []string{"chrome"})
+	assert.ErrorContains(t, err, context.Canceled.Error())
+}
+
+// mockVisitBrowser is a mockPathBrowser whose profile holds a single visit.
+type mockVisitBrowser struct {
+	mockPathBrowser
+}
+
+func (m *mockVisitBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	return history.Values([]history.HistoryEntry{{URL: "https://example.com", Profile: profile, Timestamp: endTime}}, nil)
+}
+
+func TestHistoryService_SourceReport(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+	missingPath := filepath.Join(t.TempDir(), "missing", "places.sqlite")
+	service := NewHistoryService(map[string]browser.Browser{
+		"chrome":  &mockVisitBrowser{mockPathBrowser{dbPath: dbPath}},
+		"edge":    new(MockBrowser),
+		"firefox": &mockPathBrowser{dbPath: missingPath},
+	})
+
+	t.Run("SkipsUnreadableProfiles", func(t *testing.T) {
+		var report SourceReport
+		entries, err := history.Collect(service.StreamHistory(context.Background(), &config.Config{HistoryDays: 1, EndTime: time.Now()}, []string{"chrome", "edge", "firefox"}, &report))
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		if assert.Len(t, report.Sources, 3) {
+			assert.Equal(t, history.SourceResult{Browser: "chrome", Profile: "Default", Path: dbPath, Found: true, Entries: 1}, report.Sources[0])
+			assert.Equal(t, history.SourceResult{Browser: "edge"}, report.Sources[1])
+			assert.True(t, report.Sources[2].Found)
+			assert.Contains(t, report.Sources[2].Error, missingPath)
+		}
+
+		var buf bytes.Buffer
+		service.OutputSources(&report, &buf)
+		lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+		if assert.Len(t, lines, 3) {
+			assert.Equal(t, "chrome               Default              1 entries", string(lines[0]))
+			assert.Equal(t, "edge                                      not found", string(lines[1]))
+			assert.Contains(t, string(lines[2]), "error: ")
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		var report SourceReport
+		_, err := history.Collect(service.StreamHistory(context.Background(), &config.Config{HistoryDays: 1, EndTime: time.Now(), Strict: true}, []string{"chrome", "firefox"}, &report))
+		assert.ErrorContains(t, err, "failed to read firefox history")
+		if assert.Len(t, report.Sources, 2) {
+			assert.NotEmpty(t, report.Sources[1].Error)
+		}
+	})
+}
+
+// mockLocateErrorBrowser fails to locate its profiles with err.
+type mockLocateErrorBrowser struct {
+	MockBrowser
+	err error
+}
+
+func (m *mockLocateErrorBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
+	return nil, m.err
+}
+
+func TestHistoryService_LocateErrors(t *testing.T) {
+	denied := &os.PathError{Op: "open", Path: "/home/bob/.config/google-chrome", Err: os.ErrPermission}
+	service := NewHistoryService(map[string]browser.Browser{
+		"chrome":  &mockLocateErrorBrowser{err: denied},
+		"firefox": &mockLocateErrorBrowser{err: fmt.Errorf("failed to load profiles.ini: %w", os.ErrNotExist)},
+		"safari":  &mockLocateErrorBrowser{err: fmt.Errorf("%w: linux", browser.ErrUnsupportedOS)},
+	})
+	selected := []string{"chrome", "firefox", "safari"}
+
+	t.Run("Reported", func(t *testing.T) {
+		var report SourceReport
+		_, err := history.Collect(service.StreamHistory(context.Background(), &config.Config{HistoryDays: 1, EndTime: time.Now()}, selected, &report))
+		assert.NoError(t, err)
+		if assert.Len(t, report.Sources, 3) {
+			assert.Equal(t, history.SourceResult{Browser: "chrome", Error: denied.Error()}, report.Sources[0])
+			assert.Equal(t, history.SourceResult{Browser: "firefox"}, report.Sources[1])
+			assert.Equal(t, history.SourceResult{Browser: "safari"}, report.Sources[2])
+		}
+
+		var buf bytes.Buffer
+		service.OutputSources(&report, &buf)
+		assert.Contains(t, buf.String(), "error: "+denied.Error())
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		_, err := service.GetHistory(context.Background(), &config.Config{HistoryDays: 1, EndTime: time.Now(), Strict: true}, selected)
+		assert.ErrorContains(t, err, "failed to locate chrome profiles")
+		_, err = service.GetHistory(context.Background(), &config.Config{HistoryDays: 1, EndTime: time.Now(), Strict: true}, []string{"firefox", "safari"})
+		assert.NoError(t, err)
+	})
+}
+
+// mockRangeBrowser records the time range it is asked to read.
+type mockRangeBrowser struct {
+	mockPathBrowser
+	startTime, endTime time.Time
+}
+
+func (m *mockRangeBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
+	m.startTime, m.endTime = startTime, endTime
+	return history.Values[history.HistoryEntry](nil, nil)
+}
+
+func TestHistoryService_TimeRange(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(dbPath, []byte("mock data"), 0644))
+	rangeBrowser := &mockRangeBrowser{mockPathBrowser: mockPathBrowser{dbPath: dbPath}}
+	service := NewHistoryService(map[string]browser.Browser{"chrome": rangeBrowser})
+	endTime := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("FromDays", func(t *testing.T) {
+		_, err := service.GetHistory(context.Background(), &config.Config{HistoryDays: 7, EndTime: endTime}, []string{"chrome"})
+		assert.NoError(t, err)
+		assert.Equal(t, endTime.AddDate(0, 0, -7), rangeBrowser.startTime)
+		assert.Equal(t, endTime, rangeBrowser.endTime)
+	})
+
+	t.Run("Explicit", func(t *testing.T) {
+		startTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := service.GetHistory(context.Background(), &config.Config{HistoryDays: 7, StartTime: startTime, EndTime: endTime, CustomRange: true}, []string{"chrome"})
+		assert.NoError(t, err)
+		assert.Equal(t, startTime, rangeBrowser.startTime)
+		assert.Equal(t, endTime, rangeBrowser.endTime)
+	})
+}
+
+// mockEnvironmentBrowser records the Environment it is asked to search.
+type mockEnvironmentBrowser struct {
+	MockBrowser
+	envs []browser.Environment
+}
+
+func (m *mockEnvironmentBrowser) GetHistoryPathsIn(env browser.Environment) ([]history.HistoryPathEntry, error) {
+	m.envs = append(m.envs, env)
 	return nil, nil
 }
 
+func TestHistoryService_Root(t *testing.T) {
+	envBrowser := &mockEnvironmentBrowser{}
+	service := NewHistoryService(map[string]browser.Browser{
+		"chrome": envBrowser,
+		"mock":   new(MockBrowser),
+	})
+
+	t.Run("RebasesOntoRoot", func(t *testing.T) {
+		cfg := &config.Config{HistoryDays: 1, EndTime: time.Now(), Root: "/mnt/evidence", TargetOS: "windows", Home: `C:\Users\alice`}
+		_, err := service.GetHistory(context.Background(), cfg, []string{"chrome", "mock"})
+		assert.NoError(t, err)
+		assert.Len(t, envBrowser.envs, 1)
+		assert.Equal(t, "windows", envBrowser.envs[0].OS)
+		assert.Equal(t, filepath.Join("/mnt/evidence", "Users", "alice", "AppData", "Local"), envBrowser.envs[0].Getenv("LOCALAPPDATA"))
+	})
+
+	t.Run("MissingHome", func(t *testing.T) {
+		cfg := &config.Config{Root: "/mnt/evidence", TargetOS: "linux"}
+		_, err := service.GetHistory(context.Background(), cfg, []string{"chrome"})
+		assert.Error(t, err)
+	})
+}
+
+func TestHistoryService_AllUsers(t *testing.T) {
+	root := t.TempDir()
+	for _, user := range []string{"alice", "bob"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "home", user), 0755))
+	}
+
+	t.Run("EveryUser", func(t *testing.T) {
+		envBrowser := &mockEnvironmentBrowser{}
+		service := NewHistoryService(map[string]browser.Browser{"chrome": envBrowser})
+		cfg := &config.Config{HistoryDays: 1, EndTime: time.Now(), Root: root, TargetOS: "linux", AllUsers: true}
+		_, err := service.GetHistory(context.Background(), cfg, []string{"chrome"})
+		assert.NoError(t, err)
+		assert.Len(t, envBrowser.envs, 2)
+		assert.Equal(t, "alice", envBrowser.envs[0].User)
+		assert.Equal(t, "bob", envBrowser.envs[1].User)
+	})
+
+	t.Run("UserFilter", func(t *testing.T) {
+		envBrowser := &mockEnvironmentBrowser{}
+		service := NewHistoryService(map[string]browser.Browser{"chrome": envBrowser})
+		cfg := &config.Config{HistoryDays: 1, EndTime: time.Now(), Root: root, TargetOS: "linux", Users: []string{"bob"}}
+		_, err := service.GetHistory(context.Background(), cfg, []string{"chrome"})
+		assert.NoError(t, err)
+		assert.Len(t, envBrowser.envs, 1)
+		assert.Equal(t, "bob", envBrowser.envs[0].User)
+	})
+
+	t.Run("NoMatchingUser", func(t *testing.T) {
+		service := NewHistoryService(map[string]browser.Browser{"chrome": &mockEnvironmentBrowser{}})
+		cfg := &config.Config{Root: root, TargetOS: "linux", Users: []string{"mallory"}}
+		_, err := service.GetHistory(context.Background(), cfg, []string{"chrome"})
+		assert.Error(t, err)
+	})
+}
+
 func TestHistoryService(t *testing.T) {
 	// Setup mock browser with default browsers
 	browserMap := map[string]browser.Browser{
@@ -76,7 +391,47 @@ func TestHistoryService(t *testing.T) {
 		service.OutputResults(entries, cfg, &buf)
 
 		// Match actual output: 20 chars + 11 spaces (as observed)
-		expected := "2025-04-06T12:00:00Z           Example                                            (https://example.com) [1] [0] [] [mock] [default]\n"
+		expected := "2025-04-06T12:00:00Z           Example                                            (https://example.com) [1] [0] [] [] [mock] [default]\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("OutputResults_TextWithUser", func(t *testing.T) {
+		entries := []history.OutputEntry{
+			{
+				Timestamp: "2025-04-06T12:00:00Z",
+				URL:       "https://example.com",
+				Title:     "Example",
+				Browser:   "mock",
+				Profile:   "default",
+				User:      "alice",
+			},
+		}
+
+		var buf bytes.Buffer
+		service.OutputResults(entries, cfg, &buf)
+
+		expected := "2025-04-06T12:00:00Z           Example                                            (https://example.com) [0] [0] [] [] [mock] [default] [alice]\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("OutputResults_TextWithTransition", func(t *testing.T) {
+		entries := []history.OutputEntry{
+			{
+				Timestamp:       "2025-04-06T12:00:00Z",
+				URL:             "https://example.com",
+				Title:           "Example",
+				VisitType:       history.VisitLink,
+				NativeVisitType: "LINK (CHAIN_START, CHAIN_END)",
+				Transition:      &history.PageTransition{Raw: 0x30000000, Core: "LINK", Qualifiers: []string{"CHAIN_START", "CHAIN_END"}},
+				Browser:         "chrome",
+				Profile:         "default",
+			},
+		}
+
+		var buf bytes.Buffer
+		service.OutputResults(entries, cfg, &buf)
+
+		expected := "2025-04-06T12:00:00Z           Example                                            (https://example.com) [0] [0] [link] [LINK (CHAIN_START, CHAIN_END)] [chrome] [default] [805306368]\n"
 		assert.Equal(t, expected, buf.String())
 	})
 
@@ -104,6 +459,94 @@ func TestHistoryService(t *testing.T) {
 		assert.Equal(t, entries, result)
 	})
 
+	t.Run("WriteResults_JSONLayout", func(t *testing.T) {
+		entries := []history.OutputEntry{
+			{Timestamp: "2025-04-06T12:00:00Z", URL: "https://example.com", Browser: "mock"},
+			{Timestamp: "2025-04-06T11:00:00Z", URL: "https://go.dev", Browser: "mock"},
+		}
+		for _, pretty := range []bool{false, true} {
+			jsonCfg := &config.Config{JSONOutput: true, PrettyPrint: pretty}
+			var expected, streamed bytes.Buffer
+			writeJSON(entries, jsonCfg, &expected)
+			assert.NoError(t, service.WriteResults(history.Values(entries, nil), jsonCfg, &streamed))
+			assert.Equal(t, expected.String(), streamed.String())
+		}
+
+		var empty bytes.Buffer
+		assert.NoError(t, service.WriteResults(history.Values[history.OutputEntry](nil, nil), &config.Config{JSONOutput: true}, &empty))
+		assert.Equal(t, "[]\n", empty.String())
+	})
+
+	t.Run("WriteEnvelope_JSONLayout", func(t *testing.T) {
+		entries := []history.OutputEntry{
+			{Timestamp: "2025-04-06T12:00:00Z", URL: "https://example.com", Browser: "mock"},
+			{Timestamp: "2025-04-06T11:00:00Z", URL: "https://go.dev", Browser: "mock"},
+		}
+		report := &SourceReport{Sources: []history.SourceResult{{Browser: "mock", Profile: "Default", Found: true, Entries: 2}}}
+		envelope := struct {
+			Entries []history.OutputEntry  `json:"entries"`
+			Sources []history.SourceResult `json:"sources"`
+		}{entries, report.Sources}
+		for _, pretty := range []bool{false, true} {
+			jsonCfg := &config.Config{JSONOutput: true, PrettyPrint: pretty}
+			var expected, streamed bytes.Buffer
+			writeJSON(envelope, jsonCfg, &expected)
+			assert.NoError(t, service.WriteEnvelope(history.Values(entries, nil), report, jsonCfg, &streamed))
+			assert.Equal(t, expected.String(), streamed.String())
+		}
+
+		var empty bytes.Buffer
+		assert.NoError(t, service.WriteEnvelope(history.Values[history.OutputEntry](nil, nil), &SourceReport{}, &config.Config{JSONOutput: true}, &empty))
+		assert.Equal(t, `{"entries":[],"sources":[]}`+"\n", empty.String())
+	})
+
+	t.Run("WriteResults_Error", func(t *testing.T) {
+		entries := func(yield func(history.OutputEntry, error) bool) {
+			if yield(history.OutputEntry{Timestamp: "2025-04-06T12:00:00Z", URL: "https://example.com", Browser: "mock"}, nil) {
+				yield(history.OutputEntry{}, errors.New("read failed"))
+			}
+		}
+		var buf bytes.Buffer
+		err := service.WriteResults(entries, &config.Config{}, &buf)
+		assert.EqualError(t, err, "read failed")
+		assert.Contains(t, buf.String(), "https://example.com")
+	})
+
+	t.Run("WriteResults_JSONErrorStaysValid", func(t *testing.T) {
+		entries := func(yield func(history.OutputEntry, error) bool) {
+			if yield(history.OutputEntry{Timestamp: "2025-04-06T12:00:00Z", URL: "https://example.com", Browser: "mock"}, nil) {
+				yield(history.OutputEntry{}, errors.New("read failed"))
+			}
+		}
+		for _, pretty := range []bool{false, true} {
+			jsonCfg := &config.Config{JSONOutput: true, PrettyPrint: pretty}
+			var buf bytes.Buffer
+			assert.EqualError(t, service.WriteResults(entries, jsonCfg, &buf), "read failed")
+			var written []history.OutputEntry
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &written), "output %q", buf.String())
+			assert.Len(t, written, 1)
+
+			buf.Reset()
+			report := &SourceReport{Sources: []history.SourceResult{{Browser: "mock", Found: true, Entries: 1, Error: "read failed"}}}
+			assert.EqualError(t, service.WriteEnvelope(entries, report, jsonCfg, &buf), "read failed")
+			var envelope struct {
+				Entries []history.OutputEntry  `json:"entries"`
+				Sources []history.SourceResult `json:"sources"`
+				Error   string                 `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &envelope), "output %q", buf.String())
+			assert.Len(t, envelope.Entries, 1)
+			assert.Equal(t, report.Sources, envelope.Sources)
+			assert.Equal(t, "read failed", envelope.Error)
+		}
+
+		// An error before any entry leaves the output empty for the caller to report.
+		var buf bytes.Buffer
+		err := service.WriteEnvelope(history.Values[history.OutputEntry](nil, errors.New("read failed")), &SourceReport{}, &config.Config{JSONOutput: true}, &buf)
+		assert.EqualError(t, err, "read failed")
+		assert.Empty(t, buf.String())
+	})
+
 	t.Run("OutputResults_NoEntries", func(t *testing.T) {
 		cfg.JSONOutput = false
 		var buf bytes.Buffer
@@ -118,11 +561,11 @@ func TestNewHistoryService(t *testing.T) {
 		assert.NotNil(t, service)
 		hs, ok := service.(*historyService)
 		assert.True(t, ok)
-		assert.Len(t, hs.browserMap, 4)
-		assert.Contains(t, hs.browserMap, "chrome")
-		assert.Contains(t, hs.browserMap, "edge")
-		assert.Contains(t, hs.browserMap, "firefox")
-		assert.Contains(t, hs.browserMap, "brave")
+		assert.Len(t, hs.browserMap, len(browser.Names()))
+		for _, name := range []string{"chrome", "chrome-beta", "chrome-dev", "chrome-canary", "edge", "firefox", "brave", "chromium", "vivaldi", "opera", "opera-gx", "yandex", "thorium",
+			"librewolf", "waterfox", "floorp", "zen", "tor", "palemoon", "safari", "epiphany", "falkon", "qutebrowser"} {
+			assert.Contains(t, hs.browserMap, name)
+		}
 	})
 
 	t.Run("WithCustomBrowserMap", func(t *testing.T) {
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/lotekdan/go-browser-history/internal/utils"
+)
+
+// TabService retrieves the open and recently closed tabs saved in the same profiles
+// HistoryService reads history from.
+type TabService interface {
+	GetTabs(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.TabsOutputEntry, error)
+	OutputTabs(entries []history.TabsOutputEntry, cfg *config.Config, writer io.Writer)
+}
+
+// Ensure historyService implements the interface
+var _ TabService = (*historyService)(nil)
+
+// NewTabService creates a TabService over the given browsers, or every registered browser when nil.
+func NewTabService(browserMap map[string]browser.Browser) TabService {
+	return NewHistoryService(browserMap).(*historyService)
+}
+
+// GetTabs reads the session files of the selected browsers' profiles, honouring the same
+// profile directory, archive, root and user selection as GetHistory.
+func (s *historyService) GetTabs(ctx context.Context, cfg *config.Config, selectedBrowsers []string) ([]history.TabsOutputEntry, error) {
+	sources, cleanup, explicit, err := s.locateSources(ctx, cfg, selectedBrowsers)
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	read := func(tabBrowser browser.TabBrowser, path history.HistoryPathEntry) ([]history.SessionEntry, error) {
+		return utils.ProfileSessions(ctx, tabBrowser, path, shouldLog(cfg))
+	}
+	return readProfiles(ctx, cfg, sources, explicit, "tabs", read, utils.ToTabsOutputEntries)
+}
+
+// OutputTabs writes sessions as JSON or as an indented text outline of each profile's
+// sessions, windows and tabs. Text output shows each tab's position in its history stack.
+func (s *historyService) OutputTabs(entries []history.TabsOutputEntry, cfg *config.Config, writer io.Writer) {
+	if cfg.JSONOutput {
+		writeJSON(entries, cfg, writer)
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(writer, "No tabs found.")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "[%s] [%s]", entry.Browser, entry.Profile)
+		if entry.User != "" {
+			fmt.Fprintf(writer, " [%s]", entry.User)
+		}
+		fmt.Fprintln(writer)
+		for _, session := range entry.Sessions {
+			fmt.Fprintf(writer, "  %s (updated %s)\n", session.File, session.LastUpdate)
+			for i, window := range session.Windows {
+				fmt.Fprintf(writer, "    Window %d\n", i+1)
+				outputWindowTabs(window, writer)
+			}
+			for i, window := range session.ClosedWindows {
+				fmt.Fprintf(writer, "    Closed window %d (closed %s)\n", i+1, window.ClosedAt)
+				outputWindowTabs(window, writer)
+			}
+			if len(session.ClosedTabs) > 0 {
+				fmt.Fprintln(writer, "    Closed tabs")
+				outputWindowTabs(history.WindowOutputEntry{ClosedTabs: session.ClosedTabs}, writer)
+			}
+		}
+	}
+}
+
+// outputWindowTabs writes one text line per open and recently closed tab of a window.
+func outputWindowTabs(window history.WindowOutputEntry, writer io.Writer) {
+	for _, tab := range window.Tabs {
+		outputTab(tab, tab.LastAccessed, writer)
+		if tab.Pinned {
+			fmt.Fprint(writer, " [pinned]")
+		}
+		fmt.Fprintln(writer)
+	}
+	for _, tab := range window.ClosedTabs {
+		outputTab(tab, tab.ClosedAt, writer)
+		fmt.Fprintln(writer, " [closed]")
+	}
+}
+
+func outputTab(tab history.TabOutputEntry, timestamp string, writer io.Writer) {
+	title := tab.Title
+	if title == "" {
+		title = "(no title)"
+	}
+	fmt.Fprintf(writer, "      %-30s %-50s (%s) [%d/%d]", timestamp, title, tab.URL, tab.CurrentIndex+1, len(tab.History))
+}
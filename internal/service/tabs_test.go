@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/config"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTabBrowser returns one window with a tab for every session file next to the history
+// database: sessionFiles when set, otherwise a sessionstore.jsonlz4 beside it. It keeps the
+// paths each session was read from.
+type mockTabBrowser struct {
+	mockDirectoryBrowser
+	sessionFiles []string
+	sessionPaths []string
+}
+
+func (m *mockTabBrowser) SessionFiles(historyPath string) []string {
+	if m.sessionFiles != nil {
+		return m.sessionFiles
+	}
+	return []string{filepath.Join(filepath.Dir(historyPath), "sessionstore.jsonlz4")}
+}
+
+func (m *mockTabBrowser) ExtractSession(ctx context.Context, sessionFile, sessionPath, profile string, verbose bool) (history.SessionEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return history.SessionEntry{}, err
+	}
+	m.sessionPaths = append(m.sessionPaths, sessionPath)
+	return history.SessionEntry{
+		LastUpdate: time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC),
+		Windows: []history.SessionWindow{{
+			Tabs: []history.SessionTab{{
+				URL:          "https://go.dev/",
+				Title:        "Go",
+				Navigations:  []history.SessionNavigation{{URL: "https://go.dev/", Title: "Go"}},
+				LastAccessed: time.Date(2025, 4, 6, 11, 0, 0, 0, time.UTC),
+			}},
+		}},
+		Profile: profile,
+	}, nil
+}
+
+func TestTabService_GetTabs(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "places.sqlite")
+	sessionPath := filepath.Join(dir, "sessionstore.jsonlz4")
+	recoveryPath := filepath.Join(dir, "recovery.jsonlz4")
+	for _, path := range []string{dbPath, sessionPath, recoveryPath} {
+		assert.NoError(t, os.WriteFile(path, []byte("mock data"), 0644))
+	}
+
+	service := NewTabService(map[string]browser.Browser{
+		"firefox": &mockTabBrowser{mockDirectoryBrowser: mockDirectoryBrowser{dbPath: dbPath}, sessionFiles: []string{sessionPath, recoveryPath}},
+		"safari":  &mockDirectoryBrowser{dbPath: dbPath},
+	})
+
+	t.Run("GroupsSessionsPerProfile", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "firefox", Path: dir}}}
+		entries, err := service.GetTabs(context.Background(), cfg, nil)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "firefox", entries[0].Browser)
+		assert.Equal(t, "Person 1", entries[0].Profile)
+		assert.Len(t, entries[0].Sessions, 2)
+		assert.Equal(t, "sessionstore.jsonlz4", entries[0].Sessions[0].File)
+		assert.Equal(t, "recovery.jsonlz4", entries[0].Sessions[1].File)
+		assert.Equal(t, []history.WindowOutputEntry{{
+			Tabs: []history.TabOutputEntry{{
+				URL:          "https://go.dev/",
+				Title:        "Go",
+				LastAccessed: "2025-04-06T11:00:00Z",
+				History:      []history.NavigationOutputEntry{{URL: "https://go.dev/", Title: "Go"}},
+			}},
+			ClosedTabs: []history.TabOutputEntry{},
+		}}, entries[0].Sessions[0].Windows)
+	})
+
+	t.Run("UnsupportedBrowser", func(t *testing.T) {
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "safari", Path: dir}}}
+		_, err := service.GetTabs(context.Background(), cfg, nil)
+		assert.ErrorContains(t, err, `browser "safari" does not support tabs`)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cfg := &config.Config{ProfileDirs: []config.ProfileDir{{Browser: "firefox", Path: dir}}}
+		_, err := service.GetTabs(ctx, cfg, nil)
+		assert.ErrorContains(t, err, context.Canceled.Error())
+	})
+
+	t.Run("Archives", func(t *testing.T) {
+		archivePath := filepath.Join(t.TempDir(), "profiles.zip")
+		writeZip(t, archivePath, map[string]string{
+			"bob/.mozilla/firefox/abc.default/places.sqlite":        "mock data",
+			"bob/.mozilla/firefox/abc.default/sessionstore.jsonlz4": "mock data",
+		})
+		tabBrowser := &mockTabBrowser{}
+		service := NewTabService(map[string]browser.Browser{"firefox": tabBrowser})
+		entries, err := service.GetTabs(context.Background(), &config.Config{Archives: []string{archivePath}}, nil)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "abc.default", entries[0].Profile)
+			assert.Len(t, entries[0].Sessions, 1)
+		}
+		// The extracted session file is read in place rather than copied again.
+		if assert.Len(t, tabBrowser.sessionPaths, 1) {
+			assert.True(t, strings.HasSuffix(tabBrowser.sessionPaths[0], filepath.Join("abc.default", "sessionstore.jsonlz4")))
+		}
+	})
+}
+
+func TestTabService_OutputTabs(t *testing.T) {
+	service := NewTabService(map[string]browser.Browser{})
+	entries := []history.TabsOutputEntry{{
+		Browser: "firefox",
+		Profile: "default-release",
+		Sessions: []history.SessionOutputEntry{{
+			File:       "recovery.jsonlz4",
+			LastUpdate: "2025-04-06T12:00:00Z",
+			Windows: []history.WindowOutputEntry{{
+				Tabs: []history.TabOutputEntry{{
+					URL:          "https://go.dev/doc/",
+					Title:        "Docs",
+					Pinned:       true,
+					LastAccessed: "2025-04-06T11:00:00Z",
+					CurrentIndex: 1,
+					History:      []history.NavigationOutputEntry{{URL: "https://go.dev/"}, {URL: "https://go.dev/doc/"}},
+				}},
+				ClosedTabs: []history.TabOutputEntry{{
+					URL:      "https://example.com/",
+					ClosedAt: "2025-04-06T11:30:00Z",
+					History:  []history.NavigationOutputEntry{{URL: "https://example.com/"}},
+				}},
+			}},
+			ClosedWindows: []history.WindowOutputEntry{{ClosedAt: "2025-04-05T09:00:00Z"}},
+			ClosedTabs: []history.TabOutputEntry{{
+				URL:      "https://go.dev/blog/",
+				Title:    "Blog",
+				ClosedAt: "2025-04-05T10:00:00Z",
+				History:  []history.NavigationOutputEntry{{URL: "https://go.dev/blog/"}},
+			}},
+		}},
+	}}
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputTabs(entries, &config.Config{}, &buf)
+		assert.Equal(t, "[firefox] [default-release]\n"+
+			"  recovery.jsonlz4 (updated 2025-04-06T12:00:00Z)\n"+
+			"    Window 1\n"+
+			"      2025-04-06T11:00:00Z           Docs                                               (https://go.dev/doc/) [2/2] [pinned]\n"+
+			"      2025-04-06T11:30:00Z           (no title)                                         (https://example.com/) [1/1] [closed]\n"+
+			"    Closed window 1 (closed 2025-04-05T09:00:00Z)\n"+
+			"    Closed tabs\n"+
+			"      2025-04-05T10:00:00Z           Blog                                               (https://go.dev/blog/) [1/1] [closed]\n", buf.String())
+	})
+
+	t.Run("NoEntries", func(t *testing.T) {
+		var buf bytes.Buffer
+		service.OutputTabs(nil, &config.Config{}, &buf)
+		assert.Equal(t, "No tabs found.\n", buf.String())
+	})
+}
@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// maxPreferencesSize bounds how much of a Chromium Preferences file is read from an archive.
+const maxPreferencesSize = 16 << 20
+
+// maxArchiveSize bounds how much is extracted from a single archive, so a small archive that
+// decompresses to far more than any browser profile holds cannot fill the disk.
+var maxArchiveSize int64 = 4 << 30
+
+// ArchiveProfile is a browser profile history database extracted from an archive.
+type ArchiveProfile struct {
+	Browser string // Registered browser name detected from the profile's layout
+	history.HistoryPathEntry
+}
+
+// archiveExtraction collects the files of interest while an archive is read.
+type archiveExtraction struct {
+	ctx         context.Context
+	verbose     bool
+	dir         string // temp directory the archive's files are extracted into
+	extracted   int64
+	databases   []string
+	preferences map[string][]byte // archive profile directory -> Preferences contents
+}
+
+// ExtractArchiveProfiles locates the history databases of registered browsers, such as
+// Chromium-style History and Firefox-style places.sqlite files, inside a .zip, .tar, .tar.gz
+// or .tgz archive and extracts them, with their -wal and -shm companions and the bookmarks and
+// session files kept beside them, into a new temp directory laid out like the archive. The
+// returned profiles are marked Extracted, so they are read in place rather than copied again.
+// Extraction stops when ctx is cancelled; the returned cleanup removes every extracted file.
+func ExtractArchiveProfiles(ctx context.Context, archivePath string, verbose bool) ([]ArchiveProfile, func(), error) {
+	dir, err := os.MkdirTemp("", "go-browser-history-archive-")
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	x := &archiveExtraction{
+		ctx:         ctx,
+		verbose:     verbose,
+		dir:         dir,
+		preferences: map[string][]byte{},
+	}
+
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		err = x.readZip(archivePath)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		err = x.readTar(archivePath, true)
+	case strings.HasSuffix(lower, ".tar"):
+		err = x.readTar(archivePath, false)
+	default:
+		err = fmt.Errorf("unsupported archive format (use .zip, .tar, .tar.gz or .tgz)")
+	}
+	if err != nil {
+		x.cleanup()
+		return nil, func() {}, fmt.Errorf("failed to read archive %s: %v", archivePath, err)
+	}
+
+	var profiles []ArchiveProfile
+	for _, dbPath := range x.databases {
+		browserName, _ := browser.DetectBrowser(dbPath)
+		profileDir := path.Dir(dbPath)
+		profileName := profileNameFromPreferences(x.preferences[profileDir])
+		if profileName == "" {
+			profileName = path.Base(profileDir)
+		}
+		profiles = append(profiles, ArchiveProfile{
+			Browser: browserName,
+			HistoryPathEntry: history.HistoryPathEntry{
+				Profile:     path.Base(profileDir),
+				ProfileName: profileName,
+				Path:        x.extractedPath(dbPath),
+				Extracted:   true,
+			},
+		})
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Found %d profile(s) in archive %s\n", len(profiles), archivePath)
+	}
+	return profiles, x.cleanup, nil
+}
+
+func (x *archiveExtraction) readZip(archivePath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if err := x.handle(file.Name, func() (io.ReadCloser, error) { return file.Open() }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *archiveExtraction) readTar(archivePath string, gzipped bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var stream io.Reader = file
+	if gzipped {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	reader := tar.NewReader(contextReader{ctx: x.ctx, reader: stream})
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := x.handle(header.Name, func() (io.ReadCloser, error) { return io.NopCloser(reader), nil }); err != nil {
+			return err
+		}
+	}
+}
+
+// handle extracts an archive member if it is a history database, one of its companions, a file
+// read from beside it or a Chromium Preferences file.
+func (x *archiveExtraction) handle(name string, open func() (io.ReadCloser, error)) error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, `\`, "/")), "/")
+	base := path.Base(name)
+
+	if base == "Preferences" {
+		rc, err := open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(io.LimitReader(rc, maxPreferencesSize))
+		if err != nil {
+			return err
+		}
+		x.preferences[path.Dir(name)] = data
+		return nil
+	}
+
+	dbPath := name
+	for _, s := range []string{"-wal", "-shm"} {
+		dbPath = strings.TrimSuffix(dbPath, s)
+	}
+	_, isDatabase := browser.DetectBrowser(dbPath)
+	if !isDatabase && !isProfileFile(name) {
+		return nil
+	}
+	if isDatabase && dbPath == name && !slices.Contains(x.databases, dbPath) {
+		x.databases = append(x.databases, dbPath)
+	}
+
+	rc, err := open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return x.write(name, rc)
+}
+
+// isProfileFile reports whether an archive member, given by its slash-separated path, is one
+// of the bookmarks or session files read from beside a profile's history database.
+func isProfileFile(name string) bool {
+	base, dir := path.Base(name), path.Base(path.Dir(name))
+	switch {
+	case base == browser.ChromiumBookmarksFile:
+	case dir == "Sessions" && (strings.HasPrefix(base, "Session_") || strings.HasPrefix(base, "Tabs_")):
+	case base == "Current Session", base == "Last Session", base == "Current Tabs", base == "Last Tabs":
+	case base == "sessionstore.jsonlz4", dir == "sessionstore-backups" && strings.HasSuffix(base, ".jsonlz4"):
+	default:
+		return false
+	}
+	return true
+}
+
+// extractedPath returns where the archive member name is extracted to.
+func (x *archiveExtraction) extractedPath(name string) string {
+	return filepath.Join(x.dir, filepath.FromSlash(name))
+}
+
+// write extracts the archive member name from src, failing once the archive has produced more
+// than maxArchiveSize bytes.
+func (x *archiveExtraction) write(name string, src io.Reader) error {
+	dst := x.extractedPath(name)
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", dst, err)
+	}
+	destFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file %s: %v", dst, err)
+	}
+	defer destFile.Close()
+
+	remaining := maxArchiveSize - x.extracted
+	n, err := io.Copy(destFile, io.LimitReader(contextReader{ctx: x.ctx, reader: src}, remaining+1))
+	x.extracted += n
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %v", dst, err)
+	}
+	if n > remaining {
+		return fmt.Errorf("archive extracts to more than %d bytes", maxArchiveSize)
+	}
+	if x.verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Extracted archive member to %s\n", dst)
+	}
+	return destFile.Sync()
+}
+
+func (x *archiveExtraction) cleanup() {
+	if err := os.RemoveAll(x.dir); err != nil && x.verbose {
+		fmt.Fprintf(os.Stderr, "Debug: Warning: failed to remove temp directory %s: %v\n", x.dir, err)
+	}
+}
+
+// profileNameFromPreferences returns the profile display name from Chromium Preferences contents.
+func profileNameFromPreferences(data []byte) string {
+	if data == nil {
+		return ""
+	}
+	var profileData browser.ProfileData
+	if err := json.Unmarshal(data, &profileData); err != nil {
+		return ""
+	}
+	return profileData.Profile.Name
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// archiveFixture lists the members written into test archives.
+var archiveFixture = map[string]string{
+	"alice/AppData/Local/Microsoft/Edge/User Data/Default/History":             "edge history",
+	"alice/AppData/Local/Microsoft/Edge/User Data/Default/History-wal":         "edge wal",
+	"alice/AppData/Local/Microsoft/Edge/User Data/Default/Preferences":         `{"profile":{"name":"Work"}}`,
+	"alice/AppData/Local/Microsoft/Edge/User Data/Default/Cookies":             "ignored",
+	"alice/AppData/Local/Microsoft/Edge/User Data/Default/Bookmarks":           `{"roots":{}}`,
+	"alice/AppData/Roaming/Mozilla/Firefox/Profiles/abc.default/places.sqlite": "firefox places",
+}
+
+func writeZipFixture(t *testing.T, archivePath string) {
+	file, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, contents := range archiveFixture {
+		w, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+}
+
+func writeTarGzFixture(t *testing.T, archivePath string) {
+	file, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	writer := tar.NewWriter(gz)
+	for name, contents := range archiveFixture {
+		assert.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(contents))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, gz.Close())
+}
+
+func TestExtractArchiveProfiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		write func(*testing.T, string)
+	}{
+		{"zip", "profiles.zip", writeZipFixture},
+		{"tar.gz", "profiles.tar.gz", writeTarGzFixture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), tt.file)
+			tt.write(t, archivePath)
+
+			profiles, cleanup, err := ExtractArchiveProfiles(context.Background(), archivePath, false)
+			assert.NoError(t, err)
+			assert.Len(t, profiles, 2)
+
+			byBrowser := map[string]ArchiveProfile{}
+			for _, profile := range profiles {
+				byBrowser[profile.Browser] = profile
+			}
+
+			edge, ok := byBrowser["edge"]
+			assert.True(t, ok, "expected an edge profile")
+			assert.Equal(t, "Default", edge.Profile)
+			assert.Equal(t, "Work", edge.ProfileName)
+			assert.True(t, edge.Extracted)
+			assert.True(t, strings.HasSuffix(edge.Path, filepath.FromSlash("/Edge/User Data/Default/History")), "expected the archive layout to be kept, got %s", edge.Path)
+			data, err := os.ReadFile(edge.Path)
+			assert.NoError(t, err)
+			assert.Equal(t, "edge history", string(data))
+			data, err = os.ReadFile(edge.Path + "-wal")
+			assert.NoError(t, err)
+			assert.Equal(t, "edge wal", string(data))
+			_, err = os.Stat(filepath.Join(filepath.Dir(edge.Path), "Bookmarks"))
+			assert.NoError(t, err, "expected the bookmarks file to be extracted beside the history")
+			_, err = os.Stat(filepath.Join(filepath.Dir(edge.Path), "Cookies"))
+			assert.True(t, os.IsNotExist(err), "expected unrelated files to be skipped")
+
+			firefox, ok := byBrowser["firefox"]
+			assert.True(t, ok, "expected a firefox profile")
+			assert.Equal(t, "abc.default", firefox.ProfileName)
+
+			// A second extraction of the same archive gets its own directory.
+			again, cleanupAgain, err := ExtractArchiveProfiles(context.Background(), archivePath, false)
+			assert.NoError(t, err)
+			for _, profile := range again {
+				assert.NotEqual(t, edge.Path, profile.Path)
+			}
+			cleanupAgain()
+
+			cleanup()
+			for _, path := range []string{edge.Path, edge.Path + "-wal", firefox.Path} {
+				_, err := os.Stat(path)
+				assert.True(t, os.IsNotExist(err), "expected %s to be removed", path)
+			}
+		})
+	}
+}
+
+func TestExtractArchiveProfiles_Unsupported(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "profiles.rar")
+	assert.NoError(t, os.WriteFile(archivePath, []byte("rar"), 0644))
+
+	_, _, err := ExtractArchiveProfiles(context.Background(), archivePath, false)
+	assert.Error(t, err)
+}
+
+func TestExtractArchiveProfiles_SizeLimit(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "profiles.tar.gz")
+	writeTarGzFixture(t, archivePath)
+
+	defer func(size int64) { maxArchiveSize = size }(maxArchiveSize)
+	maxArchiveSize = 20
+
+	_, _, err := ExtractArchiveProfiles(context.Background(), archivePath, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "more than 20 bytes")
+	}
+}
+
+func TestExtractArchiveProfiles_Cancelled(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "profiles.zip")
+	writeZipFixture(t, archivePath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := ExtractArchiveProfiles(ctx, archivePath, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), context.Canceled.Error())
+	}
+}
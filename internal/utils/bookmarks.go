@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ProfileBookmarks retrieves the bookmarks of a profile using the browser's extraction logic.
+// A profile without a bookmarks file has none.
+func ProfileBookmarks(ctx context.Context, browserImpl browser.BookmarkBrowser, sourceDBPath history.HistoryPathEntry, verbose bool) ([]history.BookmarkEntry, error) {
+	bookmarksFile := browserImpl.BookmarksFile(sourceDBPath.Path)
+	if _, err := os.Stat(bookmarksFile); os.IsNotExist(err) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Debug: No bookmarks file at %s\n", bookmarksFile)
+		}
+		return nil, nil
+	}
+
+	bookmarksPath, cleanup, err := PrepareProfileFile(ctx, sourceDBPath, bookmarksFile, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare bookmarks file at %s: %v", bookmarksFile, err)
+	}
+	defer cleanup()
+	bookmarks, err := browserImpl.ExtractBookmarks(ctx, bookmarksPath, sourceDBPath.ProfileName, verbose)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bookmarks {
+		bookmarks[i].Channel = sourceDBPath.Channel
+		bookmarks[i].User = sourceDBPath.User
+	}
+	return bookmarks, nil
+}
+
+func ToBookmarkOutputEntries(entries []history.BookmarkEntry, browserName string) []history.BookmarkOutputEntry {
+	var output []history.BookmarkOutputEntry
+	for _, entry := range entries {
+		dateAdded := ""
+		if !entry.DateAdded.IsZero() {
+			dateAdded = entry.DateAdded.Format(time.RFC3339)
+		}
+		output = append(output, history.BookmarkOutputEntry{
+			DateAdded: dateAdded,
+			Title:     entry.Title,
+			URL:       entry.URL,
+			Folder:    entry.Folder,
+			GUID:      entry.GUID,
+			Browser:   browserName,
+			Profile:   entry.Profile,
+			Channel:   entry.Channel,
+			User:      entry.User,
+		})
+	}
+	return output
+}
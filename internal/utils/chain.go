@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"slices"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// NavigationChains returns, for every entry matching target, the visits that led to it by
+// following ReferrerVisitID back through links and redirects, oldest first. The entries must
+// come from a single profile database since visit ids are only unique within one. A referrer
+// outside the entries ends the chain with a visit holding only its id and URL.
+func NavigationChains(entries []history.HistoryEntry, target func(history.HistoryEntry) bool) [][]history.HistoryEntry {
+	visits := make(map[int64]history.HistoryEntry, len(entries))
+	for _, entry := range entries {
+		if entry.VisitID != 0 {
+			visits[entry.VisitID] = entry
+		}
+	}
+
+	var chains [][]history.HistoryEntry
+	for _, entry := range entries {
+		if !target(entry) {
+			continue
+		}
+		chain := []history.HistoryEntry{entry}
+		seen := map[int64]bool{entry.VisitID: true}
+		for current := entry; current.ReferrerVisitID != 0 && !seen[current.ReferrerVisitID]; {
+			seen[current.ReferrerVisitID] = true
+			referrer, ok := visits[current.ReferrerVisitID]
+			if !ok {
+				chain = append(chain, history.HistoryEntry{
+					URL:     current.ReferrerURL,
+					VisitID: current.ReferrerVisitID,
+					Profile: current.Profile,
+					Channel: current.Channel,
+					User:    current.User,
+				})
+				break
+			}
+			chain = append(chain, referrer)
+			current = referrer
+		}
+		slices.Reverse(chain)
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+func ToChainOutputEntries(chains [][]history.HistoryEntry, browserName string) []history.ChainOutputEntry {
+	var output []history.ChainOutputEntry
+	for _, chain := range chains {
+		last := chain[len(chain)-1]
+		visits := ToOutputEntries(chain, browserName)
+		for i := range chain {
+			if chain[i].Timestamp.IsZero() {
+				visits[i].Timestamp = ""
+			}
+		}
+		output = append(output, history.ChainOutputEntry{
+			Browser: browserName,
+			Profile: last.Profile,
+			Channel: last.Channel,
+			User:    last.User,
+			Visits:  visits,
+		})
+	}
+	return output
+}
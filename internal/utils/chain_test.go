@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNavigationChains(t *testing.T) {
+	base := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	entries := []history.HistoryEntry{
+		{URL: "https://example.com/article", VisitID: 4, ReferrerVisitID: 3, Timestamp: base.Add(3 * time.Second), Profile: "Default"},
+		{URL: "https://t.co/abc", VisitID: 3, ReferrerVisitID: 2, ReferrerURL: "https://twitter.com/home", Timestamp: base.Add(2 * time.Second), Profile: "Default"},
+		{URL: "https://twitter.com/home", VisitID: 2, ReferrerVisitID: 1, ReferrerURL: "https://www.google.com/", Timestamp: base.Add(time.Second), Profile: "Default"},
+		{URL: "https://example.com/article", VisitID: 9, ReferrerVisitID: 9, Timestamp: base, Profile: "Default"},
+	}
+
+	chains := NavigationChains(entries, func(entry history.HistoryEntry) bool {
+		return entry.URL == "https://example.com/article"
+	})
+	assert.Len(t, chains, 2)
+
+	var urls []string
+	for _, visit := range chains[0] {
+		urls = append(urls, visit.URL)
+	}
+	assert.Equal(t, []string{"https://www.google.com/", "https://twitter.com/home", "https://t.co/abc", "https://example.com/article"}, urls)
+	assert.Equal(t, int64(1), chains[0][0].VisitID)
+	assert.True(t, chains[0][0].Timestamp.IsZero())
+
+	// A visit referring to itself ends the chain instead of looping.
+	assert.Len(t, chains[1], 1)
+
+	output := ToChainOutputEntries(chains[:1], "chrome")
+	assert.Equal(t, "chrome", output[0].Browser)
+	assert.Equal(t, "Default", output[0].Profile)
+	assert.Equal(t, "", output[0].Visits[0].Timestamp)
+	assert.Equal(t, "2025-04-06T12:00:03Z", output[0].Visits[3].Timestamp)
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ProfileDownloads retrieves the downloads recorded in a profile database using the browser's extraction logic.
+func ProfileDownloads(ctx context.Context, browserImpl browser.DownloadBrowser, sourceDBPath history.HistoryPathEntry, startTime, endTime time.Time, verbose bool) ([]history.DownloadEntry, error) {
+	historyDBPath, cleanup, err := PrepareProfileFile(ctx, sourceDBPath, sourceDBPath.Path, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare database file at %s: %v", sourceDBPath.Path, err)
+	}
+	defer cleanup()
+	downloads, err := browserImpl.ExtractDownloads(ctx, historyDBPath, sourceDBPath.ProfileName, startTime, endTime, verbose)
+	if err != nil {
+		return nil, err
+	}
+	for i := range downloads {
+		downloads[i].Channel = sourceDBPath.Channel
+		downloads[i].User = sourceDBPath.User
+	}
+	return downloads, nil
+}
+
+func ToDownloadOutputEntries(entries []history.DownloadEntry, browserName string) []history.DownloadOutputEntry {
+	var output []history.DownloadOutputEntry
+	for _, entry := range entries {
+		finalURL := ""
+		if len(entry.URLChain) > 0 {
+			finalURL = entry.URLChain[len(entry.URLChain)-1]
+		}
+		endTime := ""
+		if !entry.EndTime.IsZero() {
+			endTime = entry.EndTime.Format(time.RFC3339)
+		}
+		output = append(output, history.DownloadOutputEntry{
+			StartTime:     entry.StartTime.Format(time.RFC3339),
+			EndTime:       endTime,
+			TargetPath:    entry.TargetPath,
+			URL:           finalURL,
+			URLChain:      entry.URLChain,
+			Referrer:      entry.Referrer,
+			ReceivedBytes: entry.ReceivedBytes,
+			TotalBytes:    entry.TotalBytes,
+			DangerType:    entry.DangerType,
+			State:         entry.State,
+			MimeType:      entry.MimeType,
+			Browser:       browserName,
+			Profile:       entry.Profile,
+			Channel:       entry.Channel,
+			User:          entry.User,
+		})
+	}
+	return output
+}
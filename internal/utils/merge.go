@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// MergeByTimestamp merges sequences that are each ordered newest first into one sequence
+// ordered newest first, keeping ties in the order of seqs. Up to concurrency sequences are
+// started at once by reading their first entry, which for a profile database copies it and runs
+// its query; later entries are read as the merged sequence is iterated. Any sequence may hold
+// the newest entry, so nothing is yielded until every sequence has started, and a started
+// sequence stays open, holding its copy and query, until it is exhausted or the merged
+// sequence ends. concurrency therefore bounds the work in flight while starting, not the number
+// of open sequences. When a sequence fails,
+// skip reports whether to drop it and carry on with the others or to end the merged sequence
+// with its error; a nil skip always ends it.
+func MergeByTimestamp[T any](seqs []iter.Seq2[T, error], timestamp func(T) time.Time, concurrency int, skip func(index int, err error) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		heads := make([]mergeHead[T], len(seqs))
+		defer func() {
+			for i := range heads {
+				heads[i].stop()
+			}
+		}()
+
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, concurrency)
+		for i, seq := range seqs {
+			heads[i].next, heads[i].stop = iter.Pull2(seq)
+			slots <- struct{}{}
+			wg.Add(1)
+			go func(head *mergeHead[T]) {
+				defer wg.Done()
+				head.advance()
+				<-slots
+			}(&heads[i])
+		}
+		wg.Wait()
+
+		for {
+			newest := -1
+			for i := range heads {
+				head := &heads[i]
+				if !head.ok {
+					continue
+				}
+				if head.err != nil {
+					if skip == nil || !skip(i, head.err) {
+						var zero T
+						yield(zero, head.err)
+						return
+					}
+					head.ok = false
+					continue
+				}
+				if newest < 0 || timestamp(head.entry).After(timestamp(heads[newest].entry)) {
+					newest = i
+				}
+			}
+			if newest < 0 {
+				return
+			}
+			if !yield(heads[newest].entry, nil) {
+				return
+			}
+			heads[newest].advance()
+		}
+	}
+}
+
+// mergeHead is a sequence being merged and the entry or error it is currently positioned at.
+type mergeHead[T any] struct {
+	next  func() (T, error, bool)
+	stop  func()
+	entry T
+	err   error
+	ok    bool
+}
+
+func (h *mergeHead[T]) advance() {
+	h.entry, h.err, h.ok = h.next()
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"errors"
+	"iter"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// timedSeq yields entries at the given minutes past a fixed time, failing afterwards when err is set.
+func timedSeq(err error, minutes ...int) iter.Seq2[time.Time, error] {
+	base := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	return func(yield func(time.Time, error) bool) {
+		for _, minute := range minutes {
+			if !yield(base.Add(time.Duration(minute)*time.Minute), nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(time.Time{}, err)
+		}
+	}
+}
+
+// collectMinutes merges seqs and returns the minutes of the merged entries.
+func collectMinutes(seqs []iter.Seq2[time.Time, error], concurrency int, skip func(int, error) bool) ([]int, error) {
+	var minutes []int
+	for entry, err := range MergeByTimestamp(seqs, func(t time.Time) time.Time { return t }, concurrency, skip) {
+		if err != nil {
+			return minutes, err
+		}
+		minutes = append(minutes, entry.Minute())
+	}
+	return minutes, nil
+}
+
+func TestMergeByTimestamp(t *testing.T) {
+	t.Run("NewestFirst", func(t *testing.T) {
+		minutes, err := collectMinutes([]iter.Seq2[time.Time, error]{
+			timedSeq(nil, 9, 4, 1),
+			timedSeq(nil),
+			timedSeq(nil, 7, 4, 2),
+		}, 2, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{9, 7, 4, 4, 2, 1}, minutes)
+	})
+
+	t.Run("SkipsFailedSequence", func(t *testing.T) {
+		var skipped []int
+		minutes, err := collectMinutes([]iter.Seq2[time.Time, error]{
+			timedSeq(nil, 9, 1),
+			timedSeq(errors.New("locked"), 8),
+		}, 1, func(index int, err error) bool {
+			skipped = append(skipped, index)
+			return true
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{9, 8, 1}, minutes)
+		assert.Equal(t, []int{1}, skipped)
+	})
+
+	t.Run("EndsOnError", func(t *testing.T) {
+		minutes, err := collectMinutes([]iter.Seq2[time.Time, error]{
+			timedSeq(nil, 9, 1),
+			timedSeq(errors.New("locked"), 8),
+		}, 4, nil)
+		assert.EqualError(t, err, "locked")
+		assert.Equal(t, []int{9, 8}, minutes)
+	})
+
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		var running, peak atomic.Int32
+		var seqs []iter.Seq2[time.Time, error]
+		for i := range 8 {
+			seqs = append(seqs, func(yield func(time.Time, error) bool) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				yield(time.Date(2025, 4, 6, 12, i, 0, 0, time.UTC), nil)
+			})
+		}
+		minutes, err := collectMinutes(seqs, 3, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{7, 6, 5, 4, 3, 2, 1, 0}, minutes)
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+		assert.Greater(t, peak.Load(), int32(1))
+	})
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ExtractProfileSearchTerms reads the searches recorded in a profile's history from historyDBPath,
+// a copy of its database made with PrepareDatabaseFile, recording the profile's channel and user
+// on every entry.
+func ExtractProfileSearchTerms(ctx context.Context, browserImpl browser.SearchTermBrowser, historyDBPath string, sourceDBPath history.HistoryPathEntry, startTime, endTime time.Time, verbose bool) ([]history.SearchTermEntry, error) {
+	entries, err := browserImpl.ExtractSearchTerms(ctx, historyDBPath, sourceDBPath.ProfileName, startTime, endTime, verbose)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Channel = sourceDBPath.Channel
+		entries[i].User = sourceDBPath.User
+	}
+	return entries, nil
+}
+
+// SearchTermsFromHistory returns a search term entry for every history visit to a known search engine's results page.
+func SearchTermsFromHistory(entries []history.HistoryEntry) []history.SearchTermEntry {
+	var searches []history.SearchTermEntry
+	for _, entry := range entries {
+		engine, term, ok := browser.ParseSearchURL(entry.URL)
+		if !ok {
+			continue
+		}
+		searches = append(searches, history.SearchTermEntry{
+			Engine:    engine,
+			Term:      term,
+			URL:       entry.URL,
+			Timestamp: entry.Timestamp,
+			Source:    history.SearchSourceURL,
+			Profile:   entry.Profile,
+			Channel:   entry.Channel,
+			User:      entry.User,
+		})
+	}
+	return searches
+}
+
+func ToSearchTermOutputEntries(entries []history.SearchTermEntry, browserName string) []history.SearchTermOutputEntry {
+	var output []history.SearchTermOutputEntry
+	for _, entry := range entries {
+		output = append(output, history.SearchTermOutputEntry{
+			Timestamp: entry.Timestamp.Format(time.RFC3339),
+			Engine:    entry.Engine,
+			Term:      entry.Term,
+			URL:       entry.URL,
+			Source:    entry.Source,
+			Browser:   browserName,
+			Profile:   entry.Profile,
+			Channel:   entry.Channel,
+			User:      entry.User,
+		})
+	}
+	return output
+}
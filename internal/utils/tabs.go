@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+)
+
+// ProfileSessions reads the session files of a profile using the browser's extraction logic.
+// A profile without session files has none.
+func ProfileSessions(ctx context.Context, browserImpl browser.TabBrowser, sourceDBPath history.HistoryPathEntry, verbose bool) ([]history.SessionEntry, error) {
+	var sessions []history.SessionEntry
+	for _, sessionFile := range browserImpl.SessionFiles(sourceDBPath.Path) {
+		sessionPath, cleanup, err := PrepareProfileFile(ctx, sourceDBPath, sessionFile, verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare session file at %s: %v", sessionFile, err)
+		}
+		session, err := browserImpl.ExtractSession(ctx, sessionFile, sessionPath, sourceDBPath.ProfileName, verbose)
+		cleanup()
+		if err != nil {
+			return nil, err
+		}
+		session.File = filepath.Base(sessionFile)
+		session.Channel = sourceDBPath.Channel
+		session.User = sourceDBPath.User
+
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// ToTabsOutputEntries groups consecutive sessions of the same profile into one entry.
+func ToTabsOutputEntries(sessions []history.SessionEntry, browserName string) []history.TabsOutputEntry {
+	var output []history.TabsOutputEntry
+	for _, session := range sessions {
+		if n := len(output); n == 0 || output[n-1].Profile != session.Profile ||
+			output[n-1].Channel != session.Channel || output[n-1].User != session.User {
+			output = append(output, history.TabsOutputEntry{
+				Browser: browserName,
+				Profile: session.Profile,
+				Channel: session.Channel,
+				User:    session.User,
+			})
+		}
+		group := &output[len(output)-1]
+		group.Sessions = append(group.Sessions, history.SessionOutputEntry{
+			File:          session.File,
+			LastUpdate:    formatSessionTime(session.LastUpdate),
+			Windows:       toWindowOutputEntries(session.Windows),
+			ClosedWindows: toWindowOutputEntries(session.ClosedWindows),
+			ClosedTabs:    toTabOutputEntries(session.ClosedTabs),
+		})
+	}
+	return output
+}
+
+func toWindowOutputEntries(windows []history.SessionWindow) []history.WindowOutputEntry {
+	output := []history.WindowOutputEntry{}
+	for _, window := range windows {
+		output = append(output, history.WindowOutputEntry{
+			ClosedAt:   formatSessionTime(window.ClosedAt),
+			Tabs:       toTabOutputEntries(window.Tabs),
+			ClosedTabs: toTabOutputEntries(window.ClosedTabs),
+		})
+	}
+	return output
+}
+
+func toTabOutputEntries(tabs []history.SessionTab) []history.TabOutputEntry {
+	output := []history.TabOutputEntry{}
+	for _, tab := range tabs {
+		navigations := []history.NavigationOutputEntry{}
+		for _, navigation := range tab.Navigations {
+			navigations = append(navigations, history.NavigationOutputEntry{
+				URL:       navigation.URL,
+				Title:     navigation.Title,
+				Timestamp: formatSessionTime(navigation.Timestamp),
+			})
+		}
+		output = append(output, history.TabOutputEntry{
+			URL:          tab.URL,
+			Title:        tab.Title,
+			Pinned:       tab.Pinned,
+			LastAccessed: formatSessionTime(tab.LastAccessed),
+			ClosedAt:     formatSessionTime(tab.ClosedAt),
+			CurrentIndex: tab.CurrentIndex,
+			History:      navigations,
+		})
+	}
+	return output
+}
+
+// formatSessionTime formats a session timestamp, leaving times the browser did not record empty.
+func formatSessionTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
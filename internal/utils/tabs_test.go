@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lotekdan/go-browser-history/internal/browser"
+	"github.com/lotekdan/go-browser-history/internal/history"
+	"github.com/stretchr/testify/assert"
+)
+
+// snssTabsFile returns a Tabs_* file holding one recently closed tab with a single navigation.
+func snssTabsFile(url string, closedAt time.Time) []byte {
+	le := binary.LittleEndian
+	data := append([]byte("SNSS"), 1, 0, 0, 0)
+	appendCommand := func(id byte, payload []byte) {
+		data = le.AppendUint16(data, uint16(len(payload)+1))
+		data = append(data, id)
+		data = append(data, payload...)
+	}
+
+	// SelectedNavigationInTab: tab id, selected index and close time.
+	selected := le.AppendUint32(le.AppendUint32(nil, 1), 0)
+	appendCommand(4, le.AppendUint64(selected, uint64(browser.TimeToChromeTime(closedAt))))
+
+	// UpdateTabNavigation: a pickle of the tab id, index, URL and an empty title.
+	pickle := le.AppendUint32(le.AppendUint32(nil, 1), 0)
+	pickle = le.AppendUint32(pickle, uint32(len(url)))
+	pickle = append(pickle, url...)
+	for len(pickle)%4 != 0 {
+		pickle = append(pickle, 0)
+	}
+	pickle = le.AppendUint32(pickle, 0)
+	appendCommand(1, append(le.AppendUint32(nil, uint32(len(pickle))), pickle...))
+	return data
+}
+
+func TestProfileSessions(t *testing.T) {
+	profileDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(profileDir, "Sessions"), 0755))
+	closedAt := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+	tabsFile := filepath.Join(profileDir, "Sessions", "Tabs_13388000000000000")
+	assert.NoError(t, os.WriteFile(tabsFile, snssTabsFile("https://closed.example.com/", closedAt), 0644))
+	modified := time.Date(2025, 4, 6, 13, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(tabsFile, modified, modified))
+
+	path := history.HistoryPathEntry{Path: filepath.Join(profileDir, "History"), ProfileName: "Person 1", User: "alice"}
+	sessions, err := ProfileSessions(context.Background(), &browser.ChromeBrowser{}, path, false)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		session := sessions[0]
+		assert.Equal(t, "Tabs_13388000000000000", session.File)
+		assert.Equal(t, "alice", session.User)
+		assert.True(t, session.LastUpdate.Equal(modified), "expected the original file's modification time, got %v", session.LastUpdate)
+		if assert.Len(t, session.ClosedTabs, 1) {
+			assert.Equal(t, "https://closed.example.com/", session.ClosedTabs[0].URL)
+			assert.True(t, session.ClosedTabs[0].ClosedAt.Equal(closedAt))
+		}
+	}
+}
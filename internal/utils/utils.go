@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"path/filepath"
 	"time"
@@ -11,37 +14,86 @@ import (
 	"github.com/lotekdan/go-browser-history/internal/history"
 )
 
-// GetBrowserHistory retrieves history from the browsers profiles and pulls them into a final result.
-func GetBrowserHistory(browserImpl browser.Browser, startTime, endTime time.Time, verbose bool) ([]history.HistoryEntry, error) {
-	sourceDBPaths, err := browserImpl.GetHistoryPaths()
+// GetHistoryFromPaths retrieves history from the given profile databases using the browser's
+// extraction logic. A profile that cannot be read does not stop the others being read; the
+// entries read are returned along with the errors of every profile that failed.
+func GetHistoryFromPaths(ctx context.Context, browserImpl browser.Browser, sourceDBPaths []history.HistoryPathEntry, startTime, endTime time.Time, concurrency int, verbose bool) ([]history.HistoryEntry, error) {
+	var errs []error
+	skip := func(index int, err error) bool {
+		errs = append(errs, err)
+		return ctx.Err() == nil
+	}
+	entries, err := history.Collect(MergeByTimestamp(profileHistories(ctx, browserImpl, sourceDBPaths, startTime, endTime, verbose), HistoryTimestamp, concurrency, skip))
 	if err != nil {
-		return nil, err // Return error silently unless logged elsewhere
+		return nil, err
 	}
+	return entries, errors.Join(errs...)
+}
 
-	var history []history.HistoryEntry
+// profileHistories returns a ProfileHistory sequence for each of the given profile databases.
+func profileHistories(ctx context.Context, browserImpl browser.Browser, sourceDBPaths []history.HistoryPathEntry, startTime, endTime time.Time, verbose bool) []iter.Seq2[history.HistoryEntry, error] {
+	var seqs []iter.Seq2[history.HistoryEntry, error]
 	for _, sourceDBPath := range sourceDBPaths {
-		historyDBPath, cleanup, err := PrepareDatabaseFile(sourceDBPath.Path, verbose)
+		seqs = append(seqs, ProfileHistory(ctx, browserImpl, sourceDBPath, startTime, endTime, verbose))
+	}
+	return seqs
+}
+
+// ProfileHistory returns the history of a single profile database as a sequence that copies the
+// database when iteration starts and removes the copy once its entries have been read.
+func ProfileHistory(ctx context.Context, browserImpl browser.Browser, sourceDBPath history.HistoryPathEntry, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		historyDBPath, cleanup, err := PrepareProfileFile(ctx, sourceDBPath, sourceDBPath.Path, verbose)
 		if err != nil {
-			return nil, fmt.Errorf("failed to prepare database file at %s: %v", sourceDBPath, err)
+			yield(history.HistoryEntry{}, fmt.Errorf("failed to prepare database file at %s: %v", sourceDBPath.Path, err))
+			return
 		}
 		defer cleanup()
 
-		entries, err := browserImpl.ExtractHistory(historyDBPath, sourceDBPath.ProfileName, startTime, endTime, verbose)
-		if err != nil {
-			return nil, err
+		for entry, err := range ExtractProfileHistory(ctx, browserImpl, historyDBPath, sourceDBPath, startTime, endTime, verbose) {
+			if !yield(entry, err) {
+				return
+			}
 		}
+	}
+}
 
-		history = append(history, entries...)
+// ExtractProfileHistory reads a profile's history from historyDBPath, a copy of its database made
+// with PrepareDatabaseFile, recording the profile's channel and user on every entry.
+func ExtractProfileHistory(ctx context.Context, browserImpl browser.Browser, historyDBPath string, sourceDBPath history.HistoryPathEntry, startTime, endTime time.Time, verbose bool) iter.Seq2[history.HistoryEntry, error] {
+	return func(yield func(history.HistoryEntry, error) bool) {
+		for entry, err := range browserImpl.ExtractHistory(ctx, historyDBPath, sourceDBPath.ProfileName, startTime, endTime, verbose) {
+			if err != nil {
+				yield(history.HistoryEntry{}, err)
+				return
+			}
+			entry.Channel = sourceDBPath.Channel
+			entry.User = sourceDBPath.User
+			if !yield(entry, nil) {
+				return
+			}
+		}
 	}
-	return history, nil
 }
 
-func PrepareDatabaseFile(sourceDBPath string, verbose bool) (string, func(), error) {
+// HistoryTimestamp returns the time of a history entry's visit, for MergeByTimestamp.
+func HistoryTimestamp(entry history.HistoryEntry) time.Time {
+	return entry.Timestamp
+}
+
+// PrepareDatabaseFile copies a database and its -wal and -shm files to a temporary location so
+// it can be read while the browser holds it open. The copy stops when ctx is cancelled; the
+// returned cleanup removes the copy.
+func PrepareDatabaseFile(ctx context.Context, sourceDBPath string, verbose bool) (string, func(), error) {
+	if err := ctx.Err(); err != nil {
+		return "", func() {}, err
+	}
 	tempDir := os.TempDir()
 	tempBaseName := fmt.Sprintf("go-browser-history-%s-%d", filepath.Base(sourceDBPath), time.Now().UnixNano())
 	tempDBPath := filepath.Join(tempDir, tempBaseName)
 
-	if err := CopyFile(sourceDBPath, tempDBPath); err != nil {
+	if err := CopyFile(ctx, sourceDBPath, tempDBPath); err != nil {
+		os.Remove(tempDBPath)
 		return "", func() {}, fmt.Errorf("failed to copy database %s to %s: %v", sourceDBPath, tempDBPath, err)
 	}
 
@@ -53,7 +105,7 @@ func PrepareDatabaseFile(sourceDBPath string, verbose bool) (string, func(), err
 		srcExtra := sourceDBPath + suffix
 		dstExtra := tempDBPath + suffix
 		if _, err := os.Stat(srcExtra); err == nil {
-			if err := CopyFile(srcExtra, dstExtra); err != nil && verbose {
+			if err := CopyFile(ctx, srcExtra, dstExtra); err != nil && verbose {
 				fmt.Fprintf(os.Stderr, "Debug: Warning: failed to copy %s to %s: %v\n", srcExtra, dstExtra, err)
 			}
 		}
@@ -73,7 +125,18 @@ func PrepareDatabaseFile(sourceDBPath string, verbose bool) (string, func(), err
 	return tempDBPath, cleanup, nil
 }
 
-func CopyFile(src, dst string) error {
+// PrepareProfileFile returns a copy of file, one of the files of the profile whose history
+// database is sourceDBPath, that can be read while the browser holds it open. An extracted
+// profile is already a private copy, so its file is returned as it is.
+func PrepareProfileFile(ctx context.Context, sourceDBPath history.HistoryPathEntry, file string, verbose bool) (string, func(), error) {
+	if sourceDBPath.Extracted {
+		return file, func() {}, ctx.Err()
+	}
+	return PrepareDatabaseFile(ctx, file, verbose)
+}
+
+// CopyFile copies src to dst, stopping with ctx's error once it is cancelled.
+func CopyFile(ctx context.Context, src, dst string) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
 		return fmt.Errorf("failed to open source file %s: %v", src, err)
@@ -86,26 +149,52 @@ func CopyFile(src, dst string) error {
 	}
 	defer destFile.Close()
 
-	if _, err = io.Copy(destFile, sourceFile); err != nil {
+	if _, err = io.Copy(destFile, contextReader{ctx: ctx, reader: sourceFile}); err != nil {
 		return fmt.Errorf("failed to copy from %s to %s: %v", src, dst, err)
 	}
 
 	return destFile.Sync()
 }
 
+// contextReader fails reads with its context's error once the context is cancelled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
 func ToOutputEntries(entries []history.HistoryEntry, browserName string) []history.OutputEntry {
 	var output []history.OutputEntry
 	for _, entry := range entries {
-		output = append(output, history.OutputEntry{
-			Timestamp:  entry.Timestamp.Format(time.RFC3339),
-			Title:      entry.Title,
-			URL:        entry.URL,
-			VisitCount: entry.VisitCount,
-			Typed:      entry.Typed,
-			VisitType:  entry.VisitType,
-			Browser:    browserName,
-			Profile:    entry.Profile,
-		})
+		output = append(output, ToOutputEntry(entry, browserName))
 	}
 	return output
 }
+
+// ToOutputEntry converts a single history entry read from browserName to its output form.
+func ToOutputEntry(entry history.HistoryEntry, browserName string) history.OutputEntry {
+	return history.OutputEntry{
+		Timestamp:       entry.Timestamp.Format(time.RFC3339),
+		Title:           entry.Title,
+		URL:             entry.URL,
+		VisitCount:      entry.VisitCount,
+		Typed:           entry.Typed,
+		VisitType:       entry.VisitType,
+		NativeVisitType: entry.NativeVisitType,
+		Transition:      entry.Transition,
+		Duration:        entry.Duration.Seconds(),
+		VisitID:         entry.VisitID,
+		ReferrerVisitID: entry.ReferrerVisitID,
+		ReferrerURL:     entry.ReferrerURL,
+		Browser:         browserName,
+		Profile:         entry.Profile,
+		Channel:         entry.Channel,
+		User:            entry.User,
+	}
+}
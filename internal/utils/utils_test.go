@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
 	"testing"
@@ -22,12 +24,12 @@ func (m *MockBrowser) GetHistoryPaths() ([]history.HistoryPathEntry, error) {
 	return args.Get(0).([]history.HistoryPathEntry), args.Error(1)
 }
 
-func (m *MockBrowser) ExtractHistory(dbPath, profile string, startTime, endTime time.Time, debug bool) ([]history.HistoryEntry, error) {
+func (m *MockBrowser) ExtractHistory(ctx context.Context, dbPath, profile string, startTime, endTime time.Time, debug bool) iter.Seq2[history.HistoryEntry, error] {
 	args := m.Called(dbPath, profile, startTime, endTime, debug)
-	return args.Get(0).([]history.HistoryEntry), args.Error(1)
+	return history.Values(args.Get(0).([]history.HistoryEntry), args.Error(1))
 }
 
-func TestGetBrowserHistory(t *testing.T) {
+func TestGetHistoryFromPaths(t *testing.T) {
 	t.Run("successful_history_retrieval", func(t *testing.T) {
 		// Setup mock browser
 		mockBrowser := new(MockBrowser)
@@ -40,16 +42,16 @@ func TestGetBrowserHistory(t *testing.T) {
 		defer os.Remove(tempPath) // Cleanup
 
 		// Mock expectations
-		mockBrowser.On("GetHistoryPaths").Return([]history.HistoryPathEntry{
+		paths := []history.HistoryPathEntry{
 			{Path: tempPath, ProfileName: ""},
-		}, nil)
+		}
 		expectedEntries := []history.HistoryEntry{
 			{URL: "http://example.com"},
 		}
 		mockBrowser.On("ExtractHistory", mock.Anything, "", mock.Anything, mock.Anything, false).Return(expectedEntries, nil)
 
 		// Execute
-		history, err := GetBrowserHistory(mockBrowser, time.Time{}, time.Time{}, false)
+		history, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 1, false)
 
 		// Assert
 		assert.NoError(t, err)
@@ -57,15 +59,40 @@ func TestGetBrowserHistory(t *testing.T) {
 		mockBrowser.AssertExpectations(t)
 	})
 
+	t.Run("channel_and_user_recorded_on_entries", func(t *testing.T) {
+		mockBrowser := new(MockBrowser)
+
+		tempPath := filepath.Join(t.TempDir(), "History")
+		err := os.WriteFile(tempPath, []byte("mock data"), 0644)
+		assert.NoError(t, err)
+
+		paths := []history.HistoryPathEntry{
+			{Path: tempPath, ProfileName: "", Channel: "beta", User: "alice"},
+		}
+		mockBrowser.On("ExtractHistory", mock.Anything, "", mock.Anything, mock.Anything, false).Return([]history.HistoryEntry{
+			{URL: "http://example.com"},
+		}, nil)
+
+		entries, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 1, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "beta", entries[0].Channel)
+		assert.Equal(t, "alice", entries[0].User)
+		output := ToOutputEntries(entries, "chrome-beta")
+		assert.Equal(t, "beta", output[0].Channel)
+		assert.Equal(t, "alice", output[0].User)
+	})
+
 	t.Run("no_files_returns_empty_history", func(t *testing.T) {
 		// Setup mock browser
 		mockBrowser := new(MockBrowser)
 
 		// Mock GetHistoryPaths to return no paths
-		mockBrowser.On("GetHistoryPaths").Return([]history.HistoryPathEntry{}, nil)
+		paths := []history.HistoryPathEntry{}
 
 		// Execute
-		history, err := GetBrowserHistory(mockBrowser, time.Time{}, time.Time{}, false)
+		history, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 1, false)
 
 		// Assert
 		assert.NoError(t, err)
@@ -85,14 +112,14 @@ func TestGetBrowserHistory(t *testing.T) {
 		defer os.Remove(tempPath)
 
 		// Mock expectations
-		mockBrowser.On("GetHistoryPaths").Return([]history.HistoryPathEntry{
+		paths := []history.HistoryPathEntry{
 			{Path: tempPath, ProfileName: ""},
-		}, nil)
+		}
 		// Use typed nil for []history.HistoryEntry to avoid panic
 		mockBrowser.On("ExtractHistory", mock.Anything, "", mock.Anything, mock.Anything, false).Return(([]history.HistoryEntry)(nil), fmt.Errorf("extraction error"))
 
 		// Execute
-		history, err := GetBrowserHistory(mockBrowser, time.Time{}, time.Time{}, false)
+		history, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 1, false)
 
 		// Assert
 		assert.Error(t, err)
@@ -100,6 +127,79 @@ func TestGetBrowserHistory(t *testing.T) {
 		assert.Equal(t, "extraction error", err.Error(), "error message mismatch")
 		mockBrowser.AssertExpectations(t)
 	})
+
+	t.Run("failed_profile_does_not_stop_others", func(t *testing.T) {
+		mockBrowser := new(MockBrowser)
+
+		tempDir := t.TempDir()
+		var paths []history.HistoryPathEntry
+		for _, name := range []string{"broken", "working"} {
+			path := filepath.Join(tempDir, name)
+			assert.NoError(t, os.WriteFile(path, []byte("mock data"), 0644))
+			paths = append(paths, history.HistoryPathEntry{Path: path, ProfileName: name})
+		}
+		mockBrowser.On("ExtractHistory", mock.Anything, "broken", mock.Anything, mock.Anything, false).Return(([]history.HistoryEntry)(nil), fmt.Errorf("extraction error"))
+		mockBrowser.On("ExtractHistory", mock.Anything, "working", mock.Anything, mock.Anything, false).Return([]history.HistoryEntry{
+			{URL: "http://example.com"},
+		}, nil)
+
+		entries, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 2, false)
+
+		assert.EqualError(t, err, "extraction error")
+		assert.Equal(t, []history.HistoryEntry{{URL: "http://example.com"}}, entries)
+		mockBrowser.AssertExpectations(t)
+	})
+
+	t.Run("merged_newest_first", func(t *testing.T) {
+		tempDir := t.TempDir()
+		var paths []history.HistoryPathEntry
+		for _, name := range []string{"first.db", "second.db"} {
+			path := filepath.Join(tempDir, name)
+			assert.NoError(t, os.WriteFile(path, []byte("mock data"), 0644))
+			paths = append(paths, history.HistoryPathEntry{Path: path, ProfileName: name, Channel: "beta"})
+		}
+
+		base := time.Date(2025, 4, 6, 12, 0, 0, 0, time.UTC)
+		mockBrowser := new(MockBrowser)
+		mockBrowser.On("ExtractHistory", mock.Anything, "first.db", mock.Anything, mock.Anything, false).Return([]history.HistoryEntry{
+			{URL: "http://example.com/1", Timestamp: base.Add(10 * time.Minute)},
+			{URL: "http://example.com/3", Timestamp: base.Add(5 * time.Minute)},
+		}, nil)
+		mockBrowser.On("ExtractHistory", mock.Anything, "second.db", mock.Anything, mock.Anything, false).Return([]history.HistoryEntry{
+			{URL: "http://example.com/2", Timestamp: base.Add(8 * time.Minute)},
+			{URL: "http://example.com/4", Timestamp: base},
+		}, nil)
+
+		entries, err := GetHistoryFromPaths(context.Background(), mockBrowser, paths, time.Time{}, time.Time{}, 2, false)
+		assert.NoError(t, err)
+		var urls []string
+		for _, entry := range entries {
+			assert.Equal(t, "beta", entry.Channel)
+			urls = append(urls, entry.URL)
+		}
+		assert.Equal(t, []string{"http://example.com/1", "http://example.com/2", "http://example.com/3", "http://example.com/4"}, urls)
+	})
+}
+
+func TestProfileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(path, []byte("mock data"), 0644))
+	mockBrowser := new(MockBrowser)
+	mockBrowser.On("ExtractHistory", mock.Anything, "Default", mock.Anything, mock.Anything, false).Return([]history.HistoryEntry{
+		{URL: "http://example.com/1"},
+		{URL: "http://example.com/2"},
+	}, nil)
+
+	for entry, err := range ProfileHistory(context.Background(), mockBrowser, history.HistoryPathEntry{Path: path, ProfileName: "Default", User: "alice"}, time.Time{}, time.Time{}, false) {
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com/1", entry.URL)
+		assert.Equal(t, "alice", entry.User)
+		break
+	}
+	// Stopping early must remove the temporary copy.
+	copyPath := mockBrowser.Calls[0].Arguments.String(0)
+	_, err := os.Stat(copyPath)
+	assert.True(t, os.IsNotExist(err), "temporary copy %s was not removed", copyPath)
 }
 
 func TestHistoryFunctions(t *testing.T) {
@@ -110,3 +210,17 @@ func TestHistoryFunctions(t *testing.T) {
 		t.Errorf("ToOutputEntries() = %v, want timestamp and browser set", result)
 	}
 }
+
+func TestPrepareDatabaseFile_Cancelled(t *testing.T) {
+	sourcePath := filepath.Join(t.TempDir(), "History")
+	assert.NoError(t, os.WriteFile(sourcePath, []byte("mock data"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, cleanup, err := PrepareDatabaseFile(ctx, sourcePath, false)
+	cleanup()
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = CopyFile(ctx, sourcePath, filepath.Join(t.TempDir(), "copy"))
+	assert.ErrorContains(t, err, context.Canceled.Error())
+}